@@ -1,9 +1,11 @@
 package common
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
+	"strings"
 
 	"github.com/stretchr/objx"
 )
@@ -35,6 +37,44 @@ type FDKError struct {
 
 	//Meta can be used for your custom keys, which would be helpful for detailed error and for debugging
 	Meta objx.Map `json:"meta,omitempty"`
+
+	// Header holds the headers of the HTTP response that produced this error, if any
+	Header http.Header `json:"-"`
+
+	// Body is the raw body of the HTTP response that produced this error, if any
+	Body []byte `json:"-"`
+
+	// Err is the underlying cause, returned by Unwrap
+	Err error `json:"-"`
+}
+
+// Sentinel errors matched by errors.Is against an *FDKError with the corresponding HTTP status
+var (
+	ErrUnauthorized = errors.New("pixelbin: unauthorized")
+	ErrForbidden    = errors.New("pixelbin: forbidden")
+	ErrNotFound     = errors.New("pixelbin: not found")
+	ErrConflict     = errors.New("pixelbin: conflict")
+	ErrRateLimited  = errors.New("pixelbin: rate limited")
+)
+
+// NewFDKErrorFromResponse builds the error for a failed HTTP response.
+// The body is decoded as an FDKError when it is JSON, otherwise it is used as the message.
+// Either way the raw body is kept in Body; the error has no underlying cause.
+func NewFDKErrorFromResponse(status int, header http.Header, body []byte) *FDKError {
+	var errResp *FDKError
+	if err := json.Unmarshal(body, &errResp); err != nil || errResp == nil {
+		errResp = NewFDKError(strings.TrimSpace(string(body)))
+	}
+	if errResp.Message == "" {
+		errResp.Message = fmt.Sprintf("%d %s", status, http.StatusText(status))
+	}
+	if errResp.Meta == nil {
+		errResp.Meta = objx.Map{}
+	}
+	errResp.Status = status
+	errResp.Header = header
+	errResp.Body = body
+	return errResp
 }
 
 // NewFDKError constructs and returns new FDKError object
@@ -72,6 +112,68 @@ func (f *FDKError) SetStatus(httpStatus int) *FDKError {
 	return f
 }
 
+// SetCause sets the underlying error returned by Unwrap
+func (f *FDKError) SetCause(err error) *FDKError {
+	f.Err = err
+	return f
+}
+
+// Unwrap returns the underlying cause, if any
+func (f *FDKError) Unwrap() error {
+	return f.Err
+}
+
+// Is reports whether the error matches one of the status sentinels such as ErrNotFound
+func (f *FDKError) Is(target error) bool {
+	switch target {
+	case ErrUnauthorized:
+		return f.Status == http.StatusUnauthorized
+	case ErrForbidden:
+		return f.Status == http.StatusForbidden
+	case ErrNotFound:
+		return f.Status == http.StatusNotFound
+	case ErrConflict:
+		return f.Status == http.StatusConflict
+	case ErrRateLimited:
+		return f.Status == http.StatusTooManyRequests
+	}
+	return false
+}
+
+// IsUnauthorized reports whether err is an API error with status 401
+func IsUnauthorized(err error) bool {
+	return errors.Is(err, ErrUnauthorized)
+}
+
+// IsForbidden reports whether err is an API error with status 403
+func IsForbidden(err error) bool {
+	return errors.Is(err, ErrForbidden)
+}
+
+// IsNotFound reports whether err is an API error with status 404
+func IsNotFound(err error) bool {
+	return errors.Is(err, ErrNotFound)
+}
+
+// IsConflict reports whether err is an API error with status 409
+func IsConflict(err error) bool {
+	return errors.Is(err, ErrConflict)
+}
+
+// IsRateLimited reports whether err is an API error with status 429
+func IsRateLimited(err error) bool {
+	return errors.Is(err, ErrRateLimited)
+}
+
+// StatusCode returns the HTTP status carried by err, or 0 when err is not an *FDKError
+func StatusCode(err error) int {
+	var fdkErr *FDKError
+	if errors.As(err, &fdkErr) {
+		return fdkErr.Status
+	}
+	return 0
+}
+
 // SetRequestID sets the RequestID in the error object
 func (f *FDKError) SetRequestID(requestID string) *FDKError {
 	f.RequestID = requestID
@@ -2,7 +2,9 @@ package common
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -10,85 +12,139 @@ import (
 	"net/http"
 	"net/url"
 	"os"
-	"reflect"
+	"sort"
+	"strings"
 )
 
+// DefaultTransport is the keep-alive transport shared by all SDK calls that are not given a custom client.
+var DefaultTransport http.RoundTripper = newDefaultTransport()
+
+// DefaultHTTPClient is the client used when none is configured.
+var DefaultHTTPClient = &http.Client{Transport: DefaultTransport}
+
+func newDefaultTransport() http.RoundTripper {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	// concurrent chunk uploads all go to the same host, keep enough idle connections around for reuse
+	transport.MaxIdleConnsPerHost = 32
+	return transport
+}
+
 func HttpRequest(method string, apiUrl string, queryParams map[string]string, data interface{}, headers map[string]string) ([]byte, error) {
+	return HttpRequestWithContext(context.Background(), method, apiUrl, queryParams, data, headers)
+}
+
+// HttpRequestWithContext is like HttpRequest but the outgoing request is bound to ctx,
+// so cancelling ctx or hitting its deadline aborts the call.
+func HttpRequestWithContext(ctx context.Context, method string, apiUrl string, queryParams map[string]string, data interface{}, headers map[string]string) ([]byte, error) {
+	return HttpRequestWithClient(ctx, DefaultHTTPClient, method, apiUrl, queryParams, data, headers)
+}
+
+// HttpRequestWithClient is like HttpRequestWithContext but sends the request through client.
+// A nil client falls back to DefaultHTTPClient.
+func HttpRequestWithClient(ctx context.Context, client *http.Client, method string, apiUrl string, queryParams map[string]string, data interface{}, headers map[string]string) ([]byte, error) {
 	params := url.Values{}
+	for k, v := range queryParams {
+		params.Set(k, v)
+	}
+	res, err := DoHttpRequest(ctx, client, method, apiUrl, params, data, headers)
+	if err != nil {
+		return []byte{}, err
+	}
+	return res.Body, nil
+}
+
+// HttpResponse holds the status, headers and body of a completed HTTP call
+type HttpResponse struct {
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+}
+
+// DoHttpRequest performs the HTTP call and returns the full response.
+// Query parameters are sent for GET requests only; a key with several values is repeated, e.g. tags=a&tags=b.
+// When the server answers with an error status, both the response and an *FDKError are returned.
+func DoHttpRequest(ctx context.Context, client *http.Client, method string, apiUrl string, queryParams url.Values, data interface{}, headers map[string]string) (*HttpResponse, error) {
+	if client == nil {
+		client = DefaultHTTPClient
+	}
 	var (
 		req         *http.Request
 		err         error
 		reqBodyMap  map[string]interface{}
 		reqBodyJSON []byte
 	)
-	payload := &bytes.Reader{}
-	if method == "GET" && queryParams != nil {
-		for k, v := range queryParams {
-			params.Set(k, v)
-		}
-	}
+	var payload io.Reader = &bytes.Reader{}
+	contentLength := int64(-1)
 	if method != "GET" && data != nil {
-		reqBodyJSON, reqBodyMap, err = ConvertInterfaceToByteAndMap(data)
-		if err != nil {
-			return nil, err
-		}
-		if _, ok := reqBodyMap["file"]; ok {
-			metaValue := reflect.ValueOf(data).Elem()
-			field := metaValue.FieldByName("File")
-			if field.Type().String() == "*os.File" {
-				var file *os.File
-				var multipartContentType string
-				file = (*os.File)(field.UnsafePointer())
-				payload, multipartContentType, err = CreateMultiPartFormPayload(file, reqBodyMap)
-				if err != nil {
-					return nil, err
-				}
-				headers["content-Type"] = multipartContentType
+		if multipartBody, ok := data.(*MultipartBody); ok {
+			_, reqBodyMap, err = ConvertInterfaceToByteAndMap(multipartBody.Fields)
+			if err != nil {
+				return nil, err
 			}
+			var multipartContentType string
+			payload, multipartContentType, contentLength, err = NewMultiPartFormStream(multipartBody.File, reqBodyMap)
+			if err != nil {
+				return nil, err
+			}
+			headers["content-Type"] = multipartContentType
 		} else {
+			reqBodyJSON, _, err = ConvertInterfaceToByteAndMap(data)
+			if err != nil {
+				return nil, err
+			}
 			payload = bytes.NewReader(reqBodyJSON)
 		}
 	}
-	req, err = http.NewRequest(method, apiUrl, payload)
+	req, err = http.NewRequestWithContext(ctx, method, apiUrl, payload)
 	if err != nil {
+		// a multipart stream is already being written by its goroutine, closing it lets the goroutine exit
+		if closer, ok := payload.(io.Closer); ok {
+			closer.Close()
+		}
 		return nil, err
 	}
+	if contentLength >= 0 {
+		req.ContentLength = contentLength
+	}
 	//Setting headers
 	for k, v := range headers {
+		// net/http only honours req.Host; a raw "host" entry would be sent as a second Host header
+		if strings.EqualFold(k, "host") {
+			req.Host = v
+			continue
+		}
 		req.Header[k] = []string{v}
 	}
 	//Setting query params
-	req.URL.RawQuery = params.Encode()
+	if method == "GET" {
+		req.URL.RawQuery = queryParams.Encode()
+	}
 
-	client := http.Client{}
 	res, err := client.Do(req)
 	if err != nil {
-		return []byte{}, err
+		return nil, err
 	}
 	// read all response body
-	resData, e := processHTTPResponse(res)
-	if e != nil {
-		return []byte{}, e
-	}
-	return resData, nil
+	return processHTTPResponse(res)
+}
+
+// IsSuccessStatus reports whether status is a 2xx HTTP status
+func IsSuccessStatus(status int) bool {
+	return status >= 200 && status < 300
 }
 
-func processHTTPResponse(res *http.Response) ([]byte, error) {
-	var errResp *FDKError
+func processHTTPResponse(res *http.Response) (*HttpResponse, error) {
 	data, err := ioutil.ReadAll(res.Body)
 	if err != nil {
-		return []byte{}, err
+		return nil, err
 	}
 	// log.Println("data", string(data))
 	defer res.Body.Close()
-	if res.StatusCode != http.StatusOK {
-		err = json.Unmarshal(data, &errResp)
-		if err != nil {
-			return []byte{}, err
-		}
-		return []byte{}, errResp
+	resp := &HttpResponse{StatusCode: res.StatusCode, Header: res.Header, Body: data}
+	if !IsSuccessStatus(res.StatusCode) {
+		return resp, NewFDKErrorFromResponse(res.StatusCode, res.Header, data)
 	}
-	return data, nil
+	return resp, nil
 }
 
 func ConvertInterfaceToByteAndMap(Body interface{}) ([]byte, map[string]interface{}, error) {
@@ -104,37 +160,151 @@ func ConvertInterfaceToByteAndMap(Body interface{}) ([]byte, map[string]interfac
 	return reqBodyJSON, reqBodyMap, nil
 }
 
+// FormFile is the file part of a multipart/form-data request
+type FormFile struct {
+	// Reader yields the file content
+	Reader io.Reader
+	// Name is the file name sent with the part. When empty, the Name() of Reader is used if it has one.
+	Name string
+	// Size is the number of bytes Reader yields. When not positive, it is taken from Reader if it exposes
+	// Stat() or Len(), otherwise the request is sent without Content-Length.
+	Size int64
+}
+
+// MultipartBody is a request payload sent as multipart/form-data instead of JSON
+type MultipartBody struct {
+	File FormFile
+	// Fields is encoded like a JSON body, each top-level key becoming a form field
+	Fields interface{}
+}
+
+// fileName returns the part file name, falling back to the reader's own name
+func (f FormFile) fileName() string {
+	if f.Name != "" {
+		return f.Name
+	}
+	if named, ok := f.Reader.(interface{ Name() string }); ok && named.Name() != "" {
+		return named.Name()
+	}
+	return "file"
+}
+
+// size returns the number of bytes left in the reader when it can be known without reading it
+func (f FormFile) size() (int64, bool) {
+	if f.Size > 0 {
+		return f.Size, true
+	}
+	switch r := f.Reader.(type) {
+	case *os.File:
+		return remainingSize(r)
+	case interface{ Len() int }:
+		return int64(r.Len()), true
+	}
+	return 0, false
+}
+
+// CreateMultiPartFormPayload builds the whole multipart/form-data body in memory.
+//
+// Deprecated: use NewMultiPartFormStream, which does not buffer the file.
 func CreateMultiPartFormPayload(file *os.File, reqBodyMap map[string]interface{}) (*bytes.Reader, string, error) {
 	body := new(bytes.Buffer)
 	writer := multipart.NewWriter(body)
-	part, err := writer.CreateFormFile("file", file.Name())
+	err := writeMultiPartForm(writer, file.Name(), file, reqBodyMap)
 	if err != nil {
 		return nil, "", err
 	}
-	io.Copy(part, file)
-	contentType := writer.FormDataContentType()
-	for key, val := range reqBodyMap {
+	return bytes.NewReader(body.Bytes()), writer.FormDataContentType(), nil
+}
+
+// NewMultiPartFormStream returns a multipart/form-data body holding file and the other fields of reqBodyMap.
+// The body is written through an io.Pipe while the request is sent, so the file is never held in memory.
+// contentLength is the exact body size when the size of file is known, -1 otherwise.
+func NewMultiPartFormStream(file FormFile, reqBodyMap map[string]interface{}) (body io.ReadCloser, contentType string, contentLength int64, err error) {
+	if file.Reader == nil {
+		return nil, "", 0, errors.New("multipart file reader is nil")
+	}
+	contentLength = -1
+	fileName := file.fileName()
+	boundary := multipart.NewWriter(nil).Boundary()
+	if size, ok := file.size(); ok {
+		// the envelope is the same whatever the file content, measure it with an empty file
+		counter := &countingWriter{}
+		envelope := multipart.NewWriter(counter)
+		if err = envelope.SetBoundary(boundary); err != nil {
+			return nil, "", 0, err
+		}
+		if err = writeMultiPartForm(envelope, fileName, bytes.NewReader(nil), reqBodyMap); err != nil {
+			return nil, "", 0, err
+		}
+		contentLength = counter.n + size
+	}
+
+	pr, pw := io.Pipe()
+	writer := multipart.NewWriter(pw)
+	if err = writer.SetBoundary(boundary); err != nil {
+		return nil, "", 0, err
+	}
+	go func() {
+		// a closed reader (e.g. the request was aborted) makes the write fail and ends this goroutine
+		pw.CloseWithError(writeMultiPartForm(writer, fileName, file.Reader, reqBodyMap))
+	}()
+	return pr, writer.FormDataContentType(), contentLength, nil
+}
+
+// writeMultiPartForm writes the file part followed by the remaining fields, in key order, and closes writer
+func writeMultiPartForm(writer *multipart.Writer, fileName string, file io.Reader, reqBodyMap map[string]interface{}) error {
+	part, err := writer.CreateFormFile("file", fileName)
+	if err != nil {
+		return err
+	}
+	if _, err = io.Copy(part, file); err != nil {
+		return err
+	}
+	keys := make([]string, 0, len(reqBodyMap))
+	for key := range reqBodyMap {
 		if key != "file" {
-			// we only process primitives & array of primitives (as supported by multipart-formdata)
-			if iter, ok := val.([]interface{}); ok {
-				for _, ele := range iter {
-					err = writer.WriteField(key, fmt.Sprintf("%v", ele))
-					if err != nil {
-						return nil, "", err
-					}
-				}
-			} else {
-				err = writer.WriteField(key, fmt.Sprintf("%v", val))
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		val := reqBodyMap[key]
+		// we only process primitives & array of primitives (as supported by multipart-formdata)
+		if iter, ok := val.([]interface{}); ok {
+			for _, ele := range iter {
+				err = writer.WriteField(key, fmt.Sprintf("%v", ele))
 				if err != nil {
-					return nil, "", err
+					return err
 				}
 			}
+		} else {
+			err = writer.WriteField(key, fmt.Sprintf("%v", val))
+			if err != nil {
+				return err
+			}
 		}
 	}
-	err = writer.Close()
+	return writer.Close()
+}
+
+// remainingSize returns the number of bytes left to read from a regular file
+func remainingSize(file *os.File) (int64, bool) {
+	info, err := file.Stat()
+	if err != nil || !info.Mode().IsRegular() {
+		return 0, false
+	}
+	offset, err := file.Seek(0, io.SeekCurrent)
 	if err != nil {
-		return nil, "", err
+		return 0, false
 	}
-	payload := bytes.NewReader(body.Bytes())
-	return payload, contentType, nil
+	return info.Size() - offset, true
+}
+
+type countingWriter struct {
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	c.n += int64(len(p))
+	return len(p), nil
 }
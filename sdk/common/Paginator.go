@@ -1,40 +1,244 @@
 package common
 
-// Paginator holds paginator object
-type Paginator struct {
-	PageNo      float64
-	HasNextPage bool
-	NextID      string
-	Next        func() (interface{}, error)
-}
-
-// NewPaginator returns paginator instance
-func NewPaginator(pageType string) *Paginator {
-	if pageType == "number" {
-		return &Paginator{
-			PageNo:      1,
-			NextID:      "",
-			HasNextPage: true,
+import (
+	"context"
+	"sync"
+)
+
+// Page is one page of results of a list endpoint
+type Page[T any] struct {
+	Items []T
+	// HasNext reports whether the server has more pages after this one
+	HasNext bool
+	// NextCursor identifies the following page, cursor pagination only
+	NextCursor string
+}
+
+// PaginatorOption configures a Paginator
+type PaginatorOption func(*paginatorOptions)
+
+type paginatorOptions struct {
+	prefetch int
+}
+
+// WithPrefetch makes the paginator fetch up to pages pages ahead in the background while the current one is consumed.
+// Call Close when abandoning such a paginator before its end, so that the background fetch stops.
+func WithPrefetch(pages int) PaginatorOption {
+	return func(o *paginatorOptions) {
+		o.prefetch = pages
+	}
+}
+
+// pageResult is the outcome of fetching one page
+type pageResult[T any] struct {
+	items []T
+	more  bool
+	err   error
+}
+
+// Paginator walks every page of a list endpoint lazily, either page by page with NextPage
+// or item by item with Next, Item and Err.
+//
+//	for paginator.Next() {
+//		use(paginator.Item())
+//	}
+//	if err := paginator.Err(); err != nil {
+//		// handle err
+//	}
+type Paginator[T any] struct {
+	parent   context.Context
+	ctx      context.Context
+	cancel   context.CancelFunc
+	advance  func(ctx context.Context) pageResult[T]
+	prefetch int
+
+	startOnce sync.Once
+	pages     chan pageResult[T]
+
+	done   bool
+	closed bool
+	items  []T
+	item   T
+	err    error
+}
+
+// NewNumberPaginator returns a paginator requesting pages firstPage, firstPage+1, ... through fetch
+// until a page reports no next page or comes back empty
+func NewNumberPaginator[T any](ctx context.Context, firstPage int, fetch func(ctx context.Context, pageNo int) (*Page[T], error), opts ...PaginatorOption) *Paginator[T] {
+	pageNo := firstPage
+	return newPaginator(ctx, func(ctx context.Context) pageResult[T] {
+		page, err := fetch(ctx, pageNo)
+		if err != nil {
+			return pageResult[T]{err: err}
 		}
+		pageNo++
+		return pageResult[T]{items: page.Items, more: page.HasNext && len(page.Items) > 0}
+	}, opts)
+}
+
+// NewCursorPaginator returns a paginator requesting the page at firstCursor, then following each page's NextCursor,
+// until a page reports no next page or has no cursor
+func NewCursorPaginator[T any](ctx context.Context, firstCursor string, fetch func(ctx context.Context, cursor string) (*Page[T], error), opts ...PaginatorOption) *Paginator[T] {
+	cursor := firstCursor
+	return newPaginator(ctx, func(ctx context.Context) pageResult[T] {
+		page, err := fetch(ctx, cursor)
+		if err != nil {
+			return pageResult[T]{err: err}
+		}
+		cursor = page.NextCursor
+		return pageResult[T]{items: page.Items, more: page.HasNext && cursor != ""}
+	}, opts)
+}
+
+func newPaginator[T any](ctx context.Context, advance func(ctx context.Context) pageResult[T], opts []PaginatorOption) *Paginator[T] {
+	options := paginatorOptions{}
+	for _, opt := range opts {
+		opt(&options)
+	}
+	inner, cancel := context.WithCancel(ctx)
+	return &Paginator[T]{
+		parent:   ctx,
+		ctx:      inner,
+		cancel:   cancel,
+		advance:  advance,
+		prefetch: options.prefetch,
+	}
+}
+
+// HasNext reports whether another page may be fetched
+func (p *Paginator[T]) HasNext() bool {
+	return !p.done && p.err == nil
+}
+
+// NextPage fetches and returns the next page of items, skipping any item of the current page not yet read with Next.
+// It returns nil and no error once every page has been fetched.
+func (p *Paginator[T]) NextPage() ([]T, error) {
+	p.items = nil
+	if !p.HasNext() {
+		return nil, p.err
 	}
-	if pageType == "cursor" {
-		return &Paginator{
-			PageNo:      0,
-			NextID:      "*",
-			HasNextPage: true,
+	if err := p.parent.Err(); err != nil {
+		p.fail(err)
+		return nil, err
+	}
+	var res pageResult[T]
+	if p.prefetch > 0 {
+		p.startOnce.Do(p.startPrefetch)
+		var ok bool
+		select {
+		case res, ok = <-p.pages:
+			if !ok {
+				// the prefetch stopped because the paginator was closed or ctx is done
+				res = pageResult[T]{err: p.parent.Err()}
+			}
+		case <-p.parent.Done():
+			res = pageResult[T]{err: p.parent.Err()}
 		}
+	} else {
+		res = p.advance(p.ctx)
+	}
+	if res.err != nil {
+		p.fail(res.err)
+		return nil, res.err
 	}
-	return &Paginator{}
+	if !res.more {
+		// the last page is in, release the context of the fetches
+		p.done = true
+		p.cancel()
+	}
+	return res.items, nil
 }
 
-// HasNext return has_next value
-func (p *Paginator) HasNext() bool {
-	return p.HasNextPage
+// startPrefetch fetches pages ahead into a channel holding up to p.prefetch pages
+func (p *Paginator[T]) startPrefetch() {
+	p.pages = make(chan pageResult[T], p.prefetch)
+	go func() {
+		defer close(p.pages)
+		for {
+			res := p.advance(p.ctx)
+			select {
+			case p.pages <- res:
+			case <-p.ctx.Done():
+				return
+			}
+			if res.err != nil || !res.more {
+				return
+			}
+		}
+	}()
+}
+
+func (p *Paginator[T]) fail(err error) {
+	if err == nil {
+		// closed, not failed
+		p.done = true
+		p.cancel()
+		return
+	}
+	p.err = err
+	p.cancel()
+}
+
+// Next advances to the next item, fetching a new page when needed.
+// It returns false when every item has been seen, the paginator was closed or an error occurred, see Err.
+func (p *Paginator[T]) Next() bool {
+	if p.err != nil || p.closed {
+		return false
+	}
+	if err := p.parent.Err(); err != nil {
+		p.fail(err)
+		return false
+	}
+	for len(p.items) == 0 {
+		if !p.HasNext() {
+			return false
+		}
+		items, err := p.NextPage()
+		if err != nil {
+			return false
+		}
+		p.items = items
+	}
+	p.item, p.items = p.items[0], p.items[1:]
+	return true
+}
+
+// Item returns the current item
+func (p *Paginator[T]) Item() T {
+	return p.item
+}
+
+// Err returns the error that stopped the iteration, if any
+func (p *Paginator[T]) Err() error {
+	return p.err
+}
+
+// ForEach calls fn for every remaining item, stopping at the first error returned by fn or by a page fetch.
+// The paginator is not closed: after fn stopped it, Next carries on with the following item.
+func (p *Paginator[T]) ForEach(fn func(item T) error) error {
+	for p.Next() {
+		if err := fn(p.Item()); err != nil {
+			return err
+		}
+	}
+	return p.Err()
+}
+
+// Collect returns up to max remaining items, or all of them when max is not positive.
+// On error, the items collected so far are returned along with it.
+// The paginator is not closed: once max items are collected, Next carries on with the following item.
+func (p *Paginator[T]) Collect(max int) ([]T, error) {
+	var items []T
+	for (max <= 0 || len(items) < max) && p.Next() {
+		items = append(items, p.Item())
+	}
+	return items, p.Err()
 }
 
-// SetPaginator sets paginator instance with either next_id or page_no
-func (p *Paginator) SetPaginator(hasNext bool, pageNo int, nextID string) {
-	p.HasNextPage = hasNext
-	p.NextID = nextID
-	p.PageNo = float64(pageNo)
+// Close stops any background prefetch. Next returns false afterwards.
+func (p *Paginator[T]) Close() {
+	p.closed = true
+	p.done = true
+	p.items = nil
+	p.cancel()
 }
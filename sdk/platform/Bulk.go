@@ -0,0 +1,377 @@
+package platform
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+)
+
+// ErrNotDeleted is reported for a file that DeleteFiles did not list as deleted
+var ErrNotDeleted = errors.New("pixelbin: file was not deleted")
+
+// BulkOptions controls bulk operations such as DeleteWhere
+type BulkOptions struct {
+	// BatchSize is the number of files per request for endpoints taking several IDs, 100 by default
+	BatchSize int
+	// Concurrency is the number of requests in flight at the same time, 4 by default
+	Concurrency uint
+	// DryRun reports the files that would be affected without changing them
+	DryRun bool
+	// RateLimit caps how many batches, or files for tag and metadata edits, are started per second. 0 means no limit.
+	RateLimit float64
+}
+
+func (o BulkOptions) withDefaults() BulkOptions {
+	if o.BatchSize <= 0 {
+		o.BatchSize = 100
+	}
+	if o.Concurrency == 0 {
+		o.Concurrency = 4
+	}
+	return o
+}
+
+// BulkResult is the outcome of a bulk operation for one file
+type BulkResult struct {
+	// ID is the file _id
+	ID     string
+	FileId string
+	// Changed is set when the operation modified the file, or would have in a dry run.
+	// Files the operation leaves as they are, and files it failed on, are not changed.
+	Changed bool
+	// Err is set when the operation failed for this file
+	Err error
+}
+
+// BulkReport lists the outcome of a bulk operation for every file it selected
+type BulkReport struct {
+	DryRun  bool
+	Results []BulkResult
+}
+
+// Succeeded returns the results without error
+func (r *BulkReport) Succeeded() []BulkResult {
+	var succeeded []BulkResult
+	for _, result := range r.Results {
+		if result.Err == nil {
+			succeeded = append(succeeded, result)
+		}
+	}
+	return succeeded
+}
+
+// Failed returns the results with an error
+func (r *BulkReport) Failed() []BulkResult {
+	var failed []BulkResult
+	for _, result := range r.Results {
+		if result.Err != nil {
+			failed = append(failed, result)
+		}
+	}
+	return failed
+}
+
+// err summarises the failures of the report, nil when there are none
+func (r *BulkReport) err(operation string) error {
+	failed := r.Failed()
+	if len(failed) == 0 {
+		return nil
+	}
+	return fmt.Errorf("%s: %d of %d files failed, first %s: %w", operation, len(failed), len(r.Results), failed[0].FileId, failed[0].Err)
+}
+
+// DeleteWhere deletes every file matching p, across all pages. Folders matched by p are left alone.
+//
+// As with ListFiles, only the folder given by p.Path is searched, not its subfolders.
+// Matching files are all listed before the first deletion, then deleted in batches of BulkOptions.BatchSize IDs.
+func (c *Assets) DeleteWhere(p ListFilesXQuery, opts BulkOptions) (*BulkReport, error) {
+	return c.DeleteWhereWithContext(context.Background(), p, opts)
+}
+
+// DeleteWhereWithContext is like DeleteWhere but binds every request to ctx.
+//
+// The returned error is set when the files could not be listed, or when at least one file was not deleted;
+// the report then tells which ones.
+func (c *Assets) DeleteWhereWithContext(ctx context.Context, p ListFilesXQuery, opts BulkOptions) (*BulkReport, error) {
+	opts = opts.withDefaults()
+	p.OnlyFiles = true
+	p.OnlyFolders = false
+	items, err := c.ListFilesPaginatorWithContext(ctx, p).Collect(0)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &BulkReport{DryRun: opts.DryRun}
+	for _, item := range items {
+		if item.Type == "folder" {
+			continue
+		}
+		report.Results = append(report.Results, BulkResult{ID: item.ID, FileId: item.FileId, Changed: opts.DryRun})
+	}
+	if opts.DryRun {
+		return report, nil
+	}
+
+	started := forEachBatch(ctx, len(report.Results), opts, func(from, to int) {
+		batch := report.Results[from:to]
+		ids := make([]string, len(batch))
+		for i, result := range batch {
+			ids[i] = result.ID
+		}
+		deleted, err := c.DeleteFilesTypedWithContext(ctx, DeleteFilesXQuery{Ids: ids})
+		if err != nil {
+			for i := range batch {
+				batch[i].Err = err
+			}
+			return
+		}
+		// a success without body tells nothing about files that were kept
+		done := make(map[string]bool, len(deleted))
+		for _, file := range deleted {
+			done[file.ID] = true
+		}
+		for i := range batch {
+			if deleted == nil || done[batch[i].ID] {
+				batch[i].Changed = true
+			} else {
+				batch[i].Err = ErrNotDeleted
+			}
+		}
+	})
+	for i := started; i < len(report.Results); i++ {
+		report.Results[i].Err = ctx.Err()
+	}
+	if err = report.err("delete"); err != nil {
+		return report, err
+	}
+	return report, ctx.Err()
+}
+
+// forEachBatch calls fn for consecutive [from, to) ranges of n elements of at most opts.BatchSize,
+// running up to opts.Concurrency calls at once. Batches not started before ctx is done are skipped:
+// it returns the number of elements whose batch was started, once every started call returned.
+func forEachBatch(ctx context.Context, n int, opts BulkOptions, fn func(from, to int)) int {
+	var wg sync.WaitGroup
+	semaphore := make(chan struct{}, opts.Concurrency)
+	var tick <-chan time.Time
+	if opts.RateLimit > 0 {
+		ticker := time.NewTicker(time.Duration(float64(time.Second) / opts.RateLimit))
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+	for from := 0; from < n; from += opts.BatchSize {
+		to := from + opts.BatchSize
+		if to > n {
+			to = n
+		}
+		if tick != nil && from > 0 {
+			select {
+			case <-tick:
+			case <-ctx.Done():
+				wg.Wait()
+				return from
+			}
+		}
+		select {
+		case semaphore <- struct{}{}:
+		case <-ctx.Done():
+			wg.Wait()
+			return from
+		}
+		wg.Add(1)
+		go func(from, to int) {
+			defer wg.Done()
+			defer func() { <-semaphore }()
+			fn(from, to)
+		}(from, to)
+	}
+	wg.Wait()
+	return n
+}
+
+// BulkTarget selects the files of a bulk edit, either by file ID or with a ListFiles query
+type BulkTarget struct {
+	// FileIds lists the files to edit, e.g. "dir/asset"
+	FileIds []string
+	// Query selects the files to edit across all pages when FileIds is empty.
+	// As with ListFiles, only the folder given by Query.Path is searched.
+	Query *ListFilesXQuery
+}
+
+// AddTags adds tags to every target file, keeping their other tags
+func (c *Assets) AddTags(target BulkTarget, tags []string, opts BulkOptions) (*BulkReport, error) {
+	return c.AddTagsWithContext(context.Background(), target, tags, opts)
+}
+
+// AddTagsWithContext is like AddTags but binds every request to ctx
+func (c *Assets) AddTagsWithContext(ctx context.Context, target BulkTarget, tags []string, opts BulkOptions) (*BulkReport, error) {
+	return c.bulkEdit(ctx, "add tags", target, opts, func(file *FilesResponse) map[string]interface{} {
+		merged := append([]string{}, file.Tags...)
+		for _, tag := range tags {
+			if !containsString(merged, tag) {
+				merged = append(merged, tag)
+			}
+		}
+		if len(merged) == len(file.Tags) {
+			return nil
+		}
+		return map[string]interface{}{"tags": merged}
+	})
+}
+
+// RemoveTags removes tags from every target file, keeping their other tags
+func (c *Assets) RemoveTags(target BulkTarget, tags []string, opts BulkOptions) (*BulkReport, error) {
+	return c.RemoveTagsWithContext(context.Background(), target, tags, opts)
+}
+
+// RemoveTagsWithContext is like RemoveTags but binds every request to ctx
+func (c *Assets) RemoveTagsWithContext(ctx context.Context, target BulkTarget, tags []string, opts BulkOptions) (*BulkReport, error) {
+	return c.bulkEdit(ctx, "remove tags", target, opts, func(file *FilesResponse) map[string]interface{} {
+		kept := []string{}
+		for _, tag := range file.Tags {
+			if !containsString(tags, tag) {
+				kept = append(kept, tag)
+			}
+		}
+		if len(kept) == len(file.Tags) {
+			return nil
+		}
+		return map[string]interface{}{"tags": kept}
+	})
+}
+
+// PatchMetadata applies patch to the metadata of every target file with JSON merge patch semantics (RFC 7386):
+// nested objects are merged, a nil value removes its key and any other value replaces the existing one.
+func (c *Assets) PatchMetadata(target BulkTarget, patch map[string]interface{}, opts BulkOptions) (*BulkReport, error) {
+	return c.PatchMetadataWithContext(context.Background(), target, patch, opts)
+}
+
+// PatchMetadataWithContext is like PatchMetadata but binds every request to ctx
+func (c *Assets) PatchMetadataWithContext(ctx context.Context, target BulkTarget, patch map[string]interface{}, opts BulkOptions) (*BulkReport, error) {
+	return c.bulkEdit(ctx, "patch metadata", target, opts, func(file *FilesResponse) map[string]interface{} {
+		// compared as JSON values, so that e.g. an int of patch matches the float64 decoded from the response
+		merged := jsonValue(mergePatch(file.Metadata, patch))
+		if reflect.DeepEqual(merged, jsonValue(normaliseMetadata(file.Metadata))) {
+			return nil
+		}
+		return map[string]interface{}{"metadata": merged}
+	})
+}
+
+// bulkEdit reads every target file, asks change for the fields to update and updates the file when there are some.
+// Files that change leaves as they are count as successes without any update, and are not marked as changed.
+func (c *Assets) bulkEdit(ctx context.Context, operation string, target BulkTarget, opts BulkOptions, change func(file *FilesResponse) map[string]interface{}) (*BulkReport, error) {
+	opts = opts.withDefaults()
+	opts.BatchSize = 1
+	report := &BulkReport{DryRun: opts.DryRun}
+	if len(target.FileIds) > 0 {
+		for _, fileId := range target.FileIds {
+			report.Results = append(report.Results, BulkResult{FileId: fileId})
+		}
+	} else if target.Query != nil {
+		query := *target.Query
+		query.OnlyFiles = true
+		query.OnlyFolders = false
+		items, err := c.ListFilesPaginatorWithContext(ctx, query).Collect(0)
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range items {
+			if item.Type != "folder" {
+				report.Results = append(report.Results, BulkResult{ID: item.ID, FileId: item.FileId})
+			}
+		}
+	}
+
+	started := forEachBatch(ctx, len(report.Results), opts, func(i, _ int) {
+		result := &report.Results[i]
+		file, err := c.GetFileByFileIdTypedWithContext(ctx, GetFileByFileIdXQuery{FileId: result.FileId})
+		if err != nil {
+			result.Err = err
+			return
+		}
+		result.ID = file.ID
+		fields := change(file)
+		if fields == nil {
+			return
+		}
+		if !opts.DryRun {
+			result.Err = c.patchFile(ctx, result.FileId, fields)
+		}
+		result.Changed = result.Err == nil
+	})
+	for i := started; i < len(report.Results); i++ {
+		report.Results[i].Err = ctx.Err()
+	}
+	if err := report.err(operation); err != nil {
+		return report, err
+	}
+	return report, ctx.Err()
+}
+
+// patchFile updates the given fields of a file. Unlike UpdateFile, it can send empty tags or metadata to clear them.
+func (c *Assets) patchFile(ctx context.Context, fileId string, fields map[string]interface{}) error {
+	client := &APIClient{
+		Conf:        c.config,
+		Method:      "patch",
+		Url:         fmt.Sprintf("/service/platform/assets/v1.0/files/%s", fileId),
+		Query:       make(map[string]string),
+		Body:        fields,
+		ContentType: "application/json",
+	}
+	return client.executeInto(ctx, &FilesResponse{})
+}
+
+// mergePatch returns target with patch applied following RFC 7386, leaving target untouched
+func mergePatch(target, patch map[string]interface{}) map[string]interface{} {
+	merged := normaliseMetadata(target)
+	for key, value := range patch {
+		if value == nil {
+			delete(merged, key)
+			continue
+		}
+		if nested, ok := value.(map[string]interface{}); ok {
+			existing, _ := merged[key].(map[string]interface{})
+			merged[key] = mergePatch(existing, nested)
+			continue
+		}
+		merged[key] = value
+	}
+	return merged
+}
+
+// normaliseMetadata returns a copy of metadata, empty rather than nil
+func normaliseMetadata(metadata map[string]interface{}) map[string]interface{} {
+	copied := make(map[string]interface{}, len(metadata))
+	for key, value := range metadata {
+		copied[key] = value
+	}
+	return copied
+}
+
+// jsonValue returns v as encoding/json decodes it after encoding it, e.g. with float64 numbers
+// and []interface{} slices, or v itself when it cannot be encoded
+func jsonValue(v interface{}) interface{} {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return v
+	}
+	var decoded interface{}
+	if err = json.Unmarshal(data, &decoded); err != nil {
+		return v
+	}
+	return decoded
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,324 @@
+package platform
+
+import (
+	"context"
+	"io"
+	"io/fs"
+	"net/http"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pixelbin-io/pixelbin-go/v3/sdk/common"
+)
+
+// AssetsFS is a read-only fs.FS over the files and folders of an organization.
+//
+// Files are named after their asset name and format, e.g. the asset "dir/kitty" in jpeg format is "dir/kitty.jpeg".
+// Opening a file streams its original bytes from the file URL; private assets can only be read with WithFSSigning.
+// Folder listings are cached for the lifetime of the AssetsFS, so changes made meanwhile are not seen:
+// call FS again for a fresh view. Modification times are not available and reported as the zero time.
+type AssetsFS struct {
+	ctx    context.Context
+	assets *Assets
+
+	accessKey         string
+	token             string
+	signExpirySeconds int
+
+	mu       sync.Mutex
+	listings map[string]*fsListing
+}
+
+// fsListing is the pending or completed listing of one folder, shared by concurrent lookups
+type fsListing struct {
+	done  chan struct{}
+	items []ExploreItem
+	err   error
+}
+
+type fsOption func(*AssetsFS)
+
+// WithFSSigning signs the URLs of private assets with security.SignURL, so that they can be read.
+// The signed URLs expire after expirySeconds, 300 when 0.
+func WithFSSigning(accessKey, token string, expirySeconds int) fsOption {
+	return func(f *AssetsFS) {
+		f.accessKey = accessKey
+		f.token = token
+		if expirySeconds > 0 {
+			f.signExpirySeconds = expirySeconds
+		}
+	}
+}
+
+var (
+	_ fs.FS        = (*AssetsFS)(nil)
+	_ fs.ReadDirFS = (*AssetsFS)(nil)
+	_ fs.StatFS    = (*AssetsFS)(nil)
+)
+
+// FS returns a read-only fs.FS over the organization assets
+func (c *Assets) FS(opts ...fsOption) *AssetsFS {
+	return c.FSWithContext(context.Background(), opts...)
+}
+
+// FSWithContext is like FS but binds every request made through the returned file system to ctx
+func (c *Assets) FSWithContext(ctx context.Context, opts ...fsOption) *AssetsFS {
+	f := &AssetsFS{ctx: ctx, assets: c, signExpirySeconds: 300, listings: make(map[string]*fsListing)}
+	for _, opt := range opts {
+		opt(f)
+	}
+	return f
+}
+
+// Open opens the named file or folder. The content of a file is downloaded on the first Read.
+func (f *AssetsFS) Open(name string) (fs.File, error) {
+	item, err := f.lookup("open", name)
+	if err != nil {
+		return nil, err
+	}
+	if item.Type == "folder" {
+		entries, err := f.readDir("open", name)
+		if err != nil {
+			return nil, err
+		}
+		return &assetDir{info: assetInfo{item: item, name: path.Base(name)}, entries: entries}, nil
+	}
+	return &assetFile{fsys: f, path: name, info: assetInfo{item: item, name: path.Base(name)}}, nil
+}
+
+// Stat returns the fs.FileInfo of the named file or folder without downloading it
+func (f *AssetsFS) Stat(name string) (fs.FileInfo, error) {
+	item, err := f.lookup("stat", name)
+	if err != nil {
+		return nil, err
+	}
+	return assetInfo{item: item, name: path.Base(name)}, nil
+}
+
+// ReadDir lists the named folder, sorted by file name
+func (f *AssetsFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	item, err := f.lookup("readdir", name)
+	if err != nil {
+		return nil, err
+	}
+	if item.Type != "folder" {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: fs.ErrInvalid}
+	}
+	return f.readDir("readdir", name)
+}
+
+// readDir lists the folder at name, which is known to exist
+func (f *AssetsFS) readDir(op, name string) ([]fs.DirEntry, error) {
+	items, err := f.list(name)
+	if err != nil {
+		return nil, pathError(op, name, err)
+	}
+	entries := make([]fs.DirEntry, 0, len(items))
+	for _, item := range items {
+		entries = append(entries, fs.FileInfoToDirEntry(assetInfo{item: item, name: assetFileName(item)}))
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name() < entries[j].Name() })
+	return entries, nil
+}
+
+// lookup finds the item at name in the listing of its parent folder
+func (f *AssetsFS) lookup(op, name string) (ExploreItem, error) {
+	if !fs.ValidPath(name) {
+		return ExploreItem{}, &fs.PathError{Op: op, Path: name, Err: fs.ErrInvalid}
+	}
+	if name == "." {
+		return ExploreItem{Type: "folder", Name: "."}, nil
+	}
+	dir, base := path.Split(name)
+	items, err := f.list(strings.TrimSuffix(dir, "/"))
+	if err != nil {
+		return ExploreItem{}, pathError(op, name, err)
+	}
+	for _, item := range items {
+		if assetFileName(item) == base {
+			return item, nil
+		}
+	}
+	return ExploreItem{}, &fs.PathError{Op: op, Path: name, Err: fs.ErrNotExist}
+}
+
+// list returns every item of the folder at dir, "." and "" being the root. Each folder is listed once;
+// a failed listing is forgotten so that the next call tries again.
+func (f *AssetsFS) list(dir string) ([]ExploreItem, error) {
+	if dir == "." {
+		dir = ""
+	}
+	f.mu.Lock()
+	listing, ok := f.listings[dir]
+	if !ok {
+		listing = &fsListing{done: make(chan struct{})}
+		f.listings[dir] = listing
+	}
+	f.mu.Unlock()
+	if ok {
+		<-listing.done
+		return listing.items, listing.err
+	}
+
+	listing.items, listing.err = f.assets.ListFilesPaginatorWithContext(f.ctx, ListFilesXQuery{Path: dir}).Collect(0)
+	if listing.err != nil {
+		f.mu.Lock()
+		delete(f.listings, dir)
+		f.mu.Unlock()
+	}
+	close(listing.done)
+	return listing.items, listing.err
+}
+
+// download fetches the original bytes of item through its URL, signed for private assets
+func (f *AssetsFS) download(item ExploreItem) (io.ReadCloser, error) {
+	file, err := f.assets.GetFileByFileIdTypedWithContext(f.ctx, GetFileByFileIdXQuery{FileId: item.FileId})
+	if err != nil {
+		return nil, err
+	}
+	fileURL, err := fileDownloadURL(file, item.Access == PRIVATE, f.accessKey, f.token, f.signExpirySeconds)
+	if err != nil {
+		return nil, err
+	}
+	return downloadURL(f.ctx, f.assets.config.GetHTTPClient(), fileURL)
+}
+
+// downloadURL starts a GET of rawURL and returns the response body
+func downloadURL(ctx context.Context, client *http.Client, rawURL string) (io.ReadCloser, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	res, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if !common.IsSuccessStatus(res.StatusCode) {
+		defer res.Body.Close()
+		body, _ := io.ReadAll(res.Body)
+		return nil, common.NewFDKErrorFromResponse(res.StatusCode, res.Header, body)
+	}
+	return res.Body, nil
+}
+
+// pathError wraps an API error, reporting a 404 as fs.ErrNotExist
+func pathError(op, name string, err error) error {
+	if common.IsNotFound(err) {
+		err = fs.ErrNotExist
+	}
+	return &fs.PathError{Op: op, Path: name, Err: err}
+}
+
+// assetFileName returns the name of item in an AssetsFS: the asset name followed by its format as extension
+func assetFileName(item ExploreItem) string {
+	if item.Type == "folder" || item.Format == "" || strings.HasSuffix(item.Name, "."+item.Format) {
+		return item.Name
+	}
+	return item.Name + "." + item.Format
+}
+
+// assetInfo implements fs.FileInfo for an ExploreItem
+type assetInfo struct {
+	item ExploreItem
+	name string
+}
+
+func (i assetInfo) Name() string { return i.name }
+
+func (i assetInfo) Size() int64 {
+	if i.IsDir() {
+		return 0
+	}
+	return int64(i.item.Size)
+}
+
+func (i assetInfo) Mode() fs.FileMode {
+	if i.IsDir() {
+		return fs.ModeDir | 0o555
+	}
+	return 0o444
+}
+
+func (i assetInfo) ModTime() time.Time { return time.Time{} }
+
+func (i assetInfo) IsDir() bool { return i.item.Type == "folder" }
+
+// Sys returns the underlying ExploreItem
+func (i assetInfo) Sys() interface{} { return i.item }
+
+// assetFile is an opened file whose content is downloaded lazily
+type assetFile struct {
+	fsys   *AssetsFS
+	path   string
+	info   assetInfo
+	body   io.ReadCloser
+	closed bool
+}
+
+func (f *assetFile) Stat() (fs.FileInfo, error) {
+	return f.info, nil
+}
+
+func (f *assetFile) Read(p []byte) (int, error) {
+	if f.closed {
+		return 0, &fs.PathError{Op: "read", Path: f.path, Err: fs.ErrClosed}
+	}
+	if f.body == nil {
+		body, err := f.fsys.download(f.info.item)
+		if err != nil {
+			return 0, pathError("read", f.path, err)
+		}
+		f.body = body
+	}
+	return f.body.Read(p)
+}
+
+func (f *assetFile) Close() error {
+	if f.closed {
+		return &fs.PathError{Op: "close", Path: f.path, Err: fs.ErrClosed}
+	}
+	f.closed = true
+	if f.body != nil {
+		return f.body.Close()
+	}
+	return nil
+}
+
+// assetDir is an opened folder
+type assetDir struct {
+	info    assetInfo
+	entries []fs.DirEntry
+	offset  int
+}
+
+func (d *assetDir) Stat() (fs.FileInfo, error) {
+	return d.info, nil
+}
+
+func (d *assetDir) Read([]byte) (int, error) {
+	return 0, &fs.PathError{Op: "read", Path: d.info.name, Err: fs.ErrInvalid}
+}
+
+func (d *assetDir) Close() error {
+	return nil
+}
+
+// ReadDir returns the next n entries of the folder, or all remaining ones when n <= 0, following fs.ReadDirFile
+func (d *assetDir) ReadDir(n int) ([]fs.DirEntry, error) {
+	remaining := d.entries[d.offset:]
+	if n <= 0 {
+		d.offset = len(d.entries)
+		return remaining, nil
+	}
+	if len(remaining) == 0 {
+		return nil, io.EOF
+	}
+	if n > len(remaining) {
+		n = len(remaining)
+	}
+	d.offset += n
+	return remaining[:n], nil
+}
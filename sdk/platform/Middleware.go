@@ -0,0 +1,72 @@
+package platform
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+)
+
+// Request is a platform API call as seen by middleware
+type Request struct {
+	// Method is the upper-case HTTP method
+	Method string
+	// Path is the API path relative to PixelbinConfig.Domain, e.g. /service/platform/assets/v1.0/listFiles
+	Path string
+	// Query holds the query parameters, sent for GET requests only
+	Query map[string]string
+	// QueryValues holds the query parameters that may repeat, e.g. tags=a&tags=b, sent along with Query
+	QueryValues url.Values
+	// Body is the request payload before encoding, nil for calls without a body
+	Body interface{}
+	// Header holds the headers that will be sent. Middleware may add, change or remove entries.
+	Header map[string]string
+}
+
+// Response is the outcome of a platform API call as seen by middleware
+type Response struct {
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+}
+
+// Handler performs a platform API call
+type Handler func(ctx context.Context, req *Request) (*Response, error)
+
+// Middleware wraps a Handler to observe or alter every platform API call.
+//
+// Middleware runs once per attempt, so with a RetryPolicy it sees every retry.
+// When the server answers with an error status the handler returns both the Response and an error.
+type Middleware func(next Handler) Handler
+
+// chainMiddlewares wraps final so that the first middleware is the outermost one
+func chainMiddlewares(middlewares []Middleware, final Handler) Handler {
+	handler := final
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		handler = middlewares[i](handler)
+	}
+	return handler
+}
+
+// values returns the query parameters to send: those of Query, then those of QueryValues
+func (r *Request) values() url.Values {
+	values := make(url.Values, len(r.Query)+len(r.QueryValues))
+	for k, v := range r.Query {
+		values.Set(k, v)
+	}
+	for k, vs := range r.QueryValues {
+		values[k] = append(values[k], vs...)
+	}
+	return values
+}
+
+// cloneValues returns a deep copy of v so that middleware changes do not leak into later attempts
+func cloneValues(v url.Values) url.Values {
+	if v == nil {
+		return nil
+	}
+	c := make(url.Values, len(v))
+	for k, vs := range v {
+		c[k] = append([]string(nil), vs...)
+	}
+	return c
+}
@@ -0,0 +1,215 @@
+package platform
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/pixelbin-io/pixelbin-go/v3/sdk/common"
+)
+
+// Move moves or renames the file or folder srcPath so that it ends up at dstPath, e.g. Move("a/b", "c/d")
+// turns the folder a/b into c/d, and Move("a/logo", "c/brand") moves and renames the file a/logo.
+//
+// Missing parent folders of dstPath are created first, root first. A folder is moved by creating the destination
+// folders with CreateFolder, moving every descendant file with UpdateFile, then deleting the source folders
+// once they are empty, deepest first.
+// Folders that still hold files, e.g. because a move failed, are kept.
+// An interrupted move can be resumed by calling Move again with the same arguments. Once the move is complete,
+// i.e. srcPath is gone and dstPath exists, calling it again returns an empty report.
+func (c *Assets) Move(srcPath, dstPath string) (*BulkReport, error) {
+	return c.MoveWithContext(context.Background(), srcPath, dstPath)
+}
+
+// MoveWithContext is like Move but binds every request to ctx.
+//
+// The report holds one result per moved file. The returned error is set when srcPath could not be found
+// or listed, or when at least one file could not be moved.
+func (c *Assets) MoveWithContext(ctx context.Context, srcPath, dstPath string) (*BulkReport, error) {
+	srcPath, dstPath = strings.Trim(srcPath, "/"), strings.Trim(dstPath, "/")
+	if srcPath == "" || dstPath == "" {
+		return nil, errors.New("move: source and destination must not be the root folder")
+	}
+	if srcPath == dstPath {
+		return &BulkReport{}, nil
+	}
+
+	folder, err := c.findFolder(ctx, srcPath)
+	if err != nil {
+		return nil, err
+	}
+	if folder == nil {
+		done, err := c.moveDone(ctx, srcPath, dstPath)
+		if err != nil {
+			return nil, err
+		}
+		if done {
+			return &BulkReport{}, nil
+		}
+		return c.moveFile(ctx, srcPath, dstPath)
+	}
+	if strings.HasPrefix(dstPath+"/", srcPath+"/") {
+		return nil, fmt.Errorf("move: cannot move %s into itself", srcPath)
+	}
+	return c.moveFolder(ctx, *folder, srcPath, dstPath)
+}
+
+// findFolder returns the folder at folderPath, or nil when there is none
+func (c *Assets) findFolder(ctx context.Context, folderPath string) (*ExploreItem, error) {
+	parent, name := path.Split(folderPath)
+	items, err := c.GetFolderDetailsTypedWithContext(ctx, GetFolderDetailsXQuery{Path: strings.TrimSuffix(parent, "/"), Name: name})
+	if err != nil && !common.IsNotFound(err) {
+		return nil, err
+	}
+	for _, item := range items {
+		if item.Type == "folder" && item.Name == name {
+			return &item, nil
+		}
+	}
+	return nil, nil
+}
+
+// moveDone reports whether an earlier move already completed, given that srcPath is not a folder:
+// there is no file at srcPath either, and there is a file or folder at dstPath
+func (c *Assets) moveDone(ctx context.Context, srcPath, dstPath string) (bool, error) {
+	_, err := c.GetFileByFileIdTypedWithContext(ctx, GetFileByFileIdXQuery{FileId: srcPath})
+	if !common.IsNotFound(err) {
+		return false, err
+	}
+	folder, err := c.findFolder(ctx, dstPath)
+	if err != nil || folder != nil {
+		return folder != nil, err
+	}
+	_, err = c.GetFileByFileIdTypedWithContext(ctx, GetFileByFileIdXQuery{FileId: dstPath})
+	if common.IsNotFound(err) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+func (c *Assets) moveFile(ctx context.Context, srcPath, dstPath string) (*BulkReport, error) {
+	dstFolder, dstName := path.Split(dstPath)
+	if err := c.ensureFolder(ctx, strings.TrimSuffix(dstFolder, "/")); err != nil {
+		return nil, err
+	}
+	report := &BulkReport{Results: []BulkResult{{FileId: srcPath}}}
+	file, err := c.UpdateFileTypedWithContext(ctx, UpdateFileXQuery{FileId: srcPath, Path: strings.TrimSuffix(dstFolder, "/"), Name: dstName})
+	if err != nil {
+		report.Results[0].Err = err
+	} else if file != nil {
+		report.Results[0].ID = file.ID
+	}
+	return report, report.err("move")
+}
+
+func (c *Assets) moveFolder(ctx context.Context, src ExploreItem, srcPath, dstPath string) (*BulkReport, error) {
+	// list everything first: moving files while listing their folder would shift the pages
+	folders := []WalkEntry{{Path: srcPath, Item: src}}
+	var files []WalkEntry
+	err := c.WalkWithContext(ctx, srcPath, func(entry WalkEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() {
+			folders = append(folders, entry)
+		} else {
+			files = append(files, entry)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	target := func(p string) string {
+		return dstPath + strings.TrimPrefix(p, srcPath)
+	}
+	// folders come parent first, so once the destination and its ancestors exist every CreateFolder finds its parent
+	if err = c.ensureFolder(ctx, dstPath); err != nil {
+		return nil, err
+	}
+	for _, folder := range folders[1:] {
+		parent, name := path.Split(target(folder.Path))
+		if err = c.createFolder(ctx, strings.TrimSuffix(parent, "/"), name); err != nil {
+			return nil, err
+		}
+	}
+
+	report := &BulkReport{Results: make([]BulkResult, len(files))}
+	for i, file := range files {
+		report.Results[i] = BulkResult{ID: file.Item.ID, FileId: file.Item.FileId}
+	}
+	opts := BulkOptions{BatchSize: 1}.withDefaults()
+	started := forEachBatch(ctx, len(files), opts, func(i, _ int) {
+		_, report.Results[i].Err = c.UpdateFileTypedWithContext(ctx, UpdateFileXQuery{
+			FileId: files[i].Item.FileId,
+			Path:   target(path.Dir(files[i].Path)),
+		})
+	})
+	for i := started; i < len(files); i++ {
+		report.Results[i].Err = ctx.Err()
+	}
+
+	// deepest first, so that a folder is empty by the time it is deleted
+	sort.SliceStable(folders, func(i, j int) bool {
+		return strings.Count(folders[i].Path, "/") > strings.Count(folders[j].Path, "/")
+	})
+	for _, folder := range folders {
+		if err = c.deleteIfEmpty(ctx, folder); err != nil {
+			break
+		}
+	}
+	if moveErr := report.err("move"); moveErr != nil {
+		return report, moveErr
+	}
+	return report, err
+}
+
+// ensureFolder creates the folder at folderPath and its ancestors, root first, accepting that any of them already exists
+func (c *Assets) ensureFolder(ctx context.Context, folderPath string) error {
+	parent := ""
+	for _, name := range strings.Split(folderPath, "/") {
+		if name == "" {
+			continue
+		}
+		if err := c.createFolder(ctx, parent, name); err != nil {
+			return err
+		}
+		parent = path.Join(parent, name)
+	}
+	return nil
+}
+
+// createFolder creates the folder name in parent, accepting that it already exists
+func (c *Assets) createFolder(ctx context.Context, parent, name string) error {
+	_, err := c.CreateFolderTypedWithContext(ctx, CreateFolderXQuery{Name: name, Path: parent})
+	if err != nil && !common.IsConflict(err) {
+		return err
+	}
+	return nil
+}
+
+// deleteIfEmpty deletes folder unless something is left in it. DeleteFolder removes children too,
+// so emptiness is checked first.
+func (c *Assets) deleteIfEmpty(ctx context.Context, folder WalkEntry) error {
+	paginator := c.ListFilesPaginatorWithContext(ctx, ListFilesXQuery{Path: folder.Path})
+	defer paginator.Close()
+	items, err := paginator.Collect(1)
+	if err != nil {
+		if common.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	if len(items) > 0 {
+		return nil
+	}
+	_, err = c.DeleteFolderTypedWithContext(ctx, DeleteFolderXQuery{ID: folder.Item.ID})
+	if common.IsNotFound(err) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,76 @@
+package platform
+
+import (
+	"context"
+
+	"github.com/pixelbin-io/pixelbin-go/v3/sdk/common"
+)
+
+// ListFilesPaginator returns a paginator over every item matching p, starting at p.PageNo (or the first page).
+//
+//	paginator := pixelbin.Assets.ListFilesPaginator(platform.ListFilesXQuery{Path: "cat-photos"})
+//	for paginator.Next() {
+//		fmt.Println(paginator.Item().Name)
+//	}
+//	if err := paginator.Err(); err != nil {
+//		// handle err
+//	}
+func (c *Assets) ListFilesPaginator(
+	p ListFilesXQuery,
+	opts ...common.PaginatorOption,
+) *common.Paginator[ExploreItem] {
+	return c.ListFilesPaginatorWithContext(context.Background(), p, opts...)
+}
+
+// ListFilesPaginatorWithContext is like ListFilesPaginator but binds every page request to ctx.
+// Iteration stops with ctx's error once ctx is done.
+func (c *Assets) ListFilesPaginatorWithContext(
+	ctx context.Context,
+	p ListFilesXQuery,
+	opts ...common.PaginatorOption,
+) *common.Paginator[ExploreItem] {
+	return common.NewNumberPaginator(ctx, firstPageNo(p.PageNo), func(ctx context.Context, pageNo int) (*common.Page[ExploreItem], error) {
+		query := p
+		query.PageNo = float64(pageNo)
+		resp, err := c.ListFilesTypedWithContext(ctx, query)
+		if err != nil {
+			return nil, err
+		}
+		return &common.Page[ExploreItem]{Items: resp.Items, HasNext: resp.Page.HasNext}, nil
+	}, opts...)
+}
+
+// GetPresetsPaginator returns a paginator over every preset matching p, starting at p.PageNo (or the first page).
+// The name, transformation, archived and sort filters of p apply to every page.
+func (c *Assets) GetPresetsPaginator(
+	p GetPresetsXQuery,
+	opts ...common.PaginatorOption,
+) *common.Paginator[AddPresetResponse] {
+	return c.GetPresetsPaginatorWithContext(context.Background(), p, opts...)
+}
+
+// GetPresetsPaginatorWithContext is like GetPresetsPaginator but binds every page request to ctx.
+// Iteration stops with ctx's error once ctx is done.
+func (c *Assets) GetPresetsPaginatorWithContext(
+	ctx context.Context,
+	p GetPresetsXQuery,
+	opts ...common.PaginatorOption,
+) *common.Paginator[AddPresetResponse] {
+	return common.NewNumberPaginator(ctx, firstPageNo(p.PageNo), func(ctx context.Context, pageNo int) (*common.Page[AddPresetResponse], error) {
+		query := p
+		query.PageNo = float64(pageNo)
+		resp, err := c.GetPresetsTypedWithContext(ctx, query)
+		if err != nil {
+			return nil, err
+		}
+		return &common.Page[AddPresetResponse]{Items: resp.Items, HasNext: resp.Page.HasNext}, nil
+	}, opts...)
+}
+
+// firstPageNo returns the page a paginator starts from, pages being numbered from 1
+func firstPageNo(pageNo float64) int {
+	if pageNo > 0 {
+		return int(pageNo)
+	}
+	return 1
+}
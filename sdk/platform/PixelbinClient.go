@@ -2,14 +2,15 @@ package platform
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
-	"math"
 	"mime/multipart"
 	"net/http"
 	"net/url"
 	"os"
+	"sort"
 	"strconv"
 	"sync"
 	"time"
@@ -65,6 +66,45 @@ params: AddCredentialsXQuery
 func (c *Assets) AddCredentials(
 	p AddCredentialsXQuery,
 ) (map[string]interface{}, error) {
+	return c.AddCredentialsWithContext(context.Background(), p)
+}
+
+// AddCredentialsWithContext is like AddCredentials but binds the request to ctx.
+func (c *Assets) AddCredentialsWithContext(
+	ctx context.Context,
+	p AddCredentialsXQuery,
+) (map[string]interface{}, error) {
+	resp := map[string]interface{}{}
+	err := c.addCredentialsRequest(p).executeInto(ctx, &resp)
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// AddCredentialsTyped is like AddCredentials but decodes the response into AddCredentialsResponse.
+func (c *Assets) AddCredentialsTyped(
+	p AddCredentialsXQuery,
+) (*AddCredentialsResponse, error) {
+	return c.AddCredentialsTypedWithContext(context.Background(), p)
+}
+
+// AddCredentialsTypedWithContext is like AddCredentialsTyped but binds the request to ctx.
+func (c *Assets) AddCredentialsTypedWithContext(
+	ctx context.Context,
+	p AddCredentialsXQuery,
+) (*AddCredentialsResponse, error) {
+	resp := &AddCredentialsResponse{}
+	err := c.addCredentialsRequest(p).executeInto(ctx, resp)
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (c *Assets) addCredentialsRequest(
+	p AddCredentialsXQuery,
+) *APIClient {
 
 	type body struct {
 		Credentials map[string]interface{} `json:"credentials,omitempty"`
@@ -80,7 +120,7 @@ func (c *Assets) AddCredentials(
 
 	queryParams := make(map[string]string)
 
-	apiClient := &APIClient{
+	return &APIClient{
 		Conf:        c.config,
 		Method:      "post",
 		Url:         "/service/platform/assets/v1.0/credentials",
@@ -89,17 +129,6 @@ func (c *Assets) AddCredentials(
 		ContentType: "application/json",
 	}
 
-	response, err := apiClient.Execute()
-	if err != nil {
-		return nil, err
-	}
-	resp := map[string]interface{}{}
-	err = json.Unmarshal(response, &resp)
-	if err != nil {
-		return nil, common.NewFDKError(err.Error())
-	}
-	return resp, nil
-
 }
 
 type UpdateCredentialsXQuery struct {
@@ -117,6 +146,45 @@ params: UpdateCredentialsXQuery
 func (c *Assets) UpdateCredentials(
 	p UpdateCredentialsXQuery,
 ) (map[string]interface{}, error) {
+	return c.UpdateCredentialsWithContext(context.Background(), p)
+}
+
+// UpdateCredentialsWithContext is like UpdateCredentials but binds the request to ctx.
+func (c *Assets) UpdateCredentialsWithContext(
+	ctx context.Context,
+	p UpdateCredentialsXQuery,
+) (map[string]interface{}, error) {
+	resp := map[string]interface{}{}
+	err := c.updateCredentialsRequest(p).executeInto(ctx, &resp)
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// UpdateCredentialsTyped is like UpdateCredentials but decodes the response into AddCredentialsResponse.
+func (c *Assets) UpdateCredentialsTyped(
+	p UpdateCredentialsXQuery,
+) (*AddCredentialsResponse, error) {
+	return c.UpdateCredentialsTypedWithContext(context.Background(), p)
+}
+
+// UpdateCredentialsTypedWithContext is like UpdateCredentialsTyped but binds the request to ctx.
+func (c *Assets) UpdateCredentialsTypedWithContext(
+	ctx context.Context,
+	p UpdateCredentialsXQuery,
+) (*AddCredentialsResponse, error) {
+	resp := &AddCredentialsResponse{}
+	err := c.updateCredentialsRequest(p).executeInto(ctx, resp)
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (c *Assets) updateCredentialsRequest(
+	p UpdateCredentialsXQuery,
+) *APIClient {
 
 	type body struct {
 		Credentials map[string]interface{} `json:"credentials,omitempty"`
@@ -128,7 +196,7 @@ func (c *Assets) UpdateCredentials(
 
 	queryParams := make(map[string]string)
 
-	apiClient := &APIClient{
+	return &APIClient{
 		Conf:        c.config,
 		Method:      "patch",
 		Url:         fmt.Sprintf("/service/platform/assets/v1.0/credentials/%s", p.PluginId),
@@ -137,17 +205,6 @@ func (c *Assets) UpdateCredentials(
 		ContentType: "application/json",
 	}
 
-	response, err := apiClient.Execute()
-	if err != nil {
-		return nil, err
-	}
-	resp := map[string]interface{}{}
-	err = json.Unmarshal(response, &resp)
-	if err != nil {
-		return nil, common.NewFDKError(err.Error())
-	}
-	return resp, nil
-
 }
 
 type DeleteCredentialsXQuery struct {
@@ -164,10 +221,49 @@ params: DeleteCredentialsXQuery
 func (c *Assets) DeleteCredentials(
 	p DeleteCredentialsXQuery,
 ) (map[string]interface{}, error) {
+	return c.DeleteCredentialsWithContext(context.Background(), p)
+}
+
+// DeleteCredentialsWithContext is like DeleteCredentials but binds the request to ctx.
+func (c *Assets) DeleteCredentialsWithContext(
+	ctx context.Context,
+	p DeleteCredentialsXQuery,
+) (map[string]interface{}, error) {
+	resp := map[string]interface{}{}
+	err := c.deleteCredentialsRequest(p).executeInto(ctx, &resp)
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// DeleteCredentialsTyped is like DeleteCredentials but decodes the response into AddCredentialsResponse.
+func (c *Assets) DeleteCredentialsTyped(
+	p DeleteCredentialsXQuery,
+) (*AddCredentialsResponse, error) {
+	return c.DeleteCredentialsTypedWithContext(context.Background(), p)
+}
+
+// DeleteCredentialsTypedWithContext is like DeleteCredentialsTyped but binds the request to ctx.
+func (c *Assets) DeleteCredentialsTypedWithContext(
+	ctx context.Context,
+	p DeleteCredentialsXQuery,
+) (*AddCredentialsResponse, error) {
+	resp := &AddCredentialsResponse{}
+	err := c.deleteCredentialsRequest(p).executeInto(ctx, resp)
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (c *Assets) deleteCredentialsRequest(
+	p DeleteCredentialsXQuery,
+) *APIClient {
 
 	queryParams := make(map[string]string)
 
-	apiClient := &APIClient{
+	return &APIClient{
 		Conf:        c.config,
 		Method:      "delete",
 		Url:         fmt.Sprintf("/service/platform/assets/v1.0/credentials/%s", p.PluginId),
@@ -176,17 +272,6 @@ func (c *Assets) DeleteCredentials(
 		ContentType: "",
 	}
 
-	response, err := apiClient.Execute()
-	if err != nil {
-		return nil, err
-	}
-	resp := map[string]interface{}{}
-	err = json.Unmarshal(response, &resp)
-	if err != nil {
-		return nil, common.NewFDKError(err.Error())
-	}
-	return resp, nil
-
 }
 
 type GetFileByIdXQuery struct {
@@ -203,10 +288,49 @@ params: GetFileByIdXQuery
 func (c *Assets) GetFileById(
 	p GetFileByIdXQuery,
 ) (map[string]interface{}, error) {
+	return c.GetFileByIdWithContext(context.Background(), p)
+}
+
+// GetFileByIdWithContext is like GetFileById but binds the request to ctx.
+func (c *Assets) GetFileByIdWithContext(
+	ctx context.Context,
+	p GetFileByIdXQuery,
+) (map[string]interface{}, error) {
+	resp := map[string]interface{}{}
+	err := c.getFileByIdRequest(p).executeInto(ctx, &resp)
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// GetFileByIdTyped is like GetFileById but decodes the response into FilesResponse.
+func (c *Assets) GetFileByIdTyped(
+	p GetFileByIdXQuery,
+) (*FilesResponse, error) {
+	return c.GetFileByIdTypedWithContext(context.Background(), p)
+}
+
+// GetFileByIdTypedWithContext is like GetFileByIdTyped but binds the request to ctx.
+func (c *Assets) GetFileByIdTypedWithContext(
+	ctx context.Context,
+	p GetFileByIdXQuery,
+) (*FilesResponse, error) {
+	resp := &FilesResponse{}
+	err := c.getFileByIdRequest(p).executeInto(ctx, resp)
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (c *Assets) getFileByIdRequest(
+	p GetFileByIdXQuery,
+) *APIClient {
 
 	queryParams := make(map[string]string)
 
-	apiClient := &APIClient{
+	return &APIClient{
 		Conf:        c.config,
 		Method:      "get",
 		Url:         fmt.Sprintf("/service/platform/assets/v1.0/files/id/%s", p.ID),
@@ -215,17 +339,6 @@ func (c *Assets) GetFileById(
 		ContentType: "",
 	}
 
-	response, err := apiClient.Execute()
-	if err != nil {
-		return nil, err
-	}
-	resp := map[string]interface{}{}
-	err = json.Unmarshal(response, &resp)
-	if err != nil {
-		return nil, common.NewFDKError(err.Error())
-	}
-	return resp, nil
-
 }
 
 type GetFileByFileIdXQuery struct {
@@ -242,10 +355,49 @@ params: GetFileByFileIdXQuery
 func (c *Assets) GetFileByFileId(
 	p GetFileByFileIdXQuery,
 ) (map[string]interface{}, error) {
+	return c.GetFileByFileIdWithContext(context.Background(), p)
+}
+
+// GetFileByFileIdWithContext is like GetFileByFileId but binds the request to ctx.
+func (c *Assets) GetFileByFileIdWithContext(
+	ctx context.Context,
+	p GetFileByFileIdXQuery,
+) (map[string]interface{}, error) {
+	resp := map[string]interface{}{}
+	err := c.getFileByFileIdRequest(p).executeInto(ctx, &resp)
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// GetFileByFileIdTyped is like GetFileByFileId but decodes the response into FilesResponse.
+func (c *Assets) GetFileByFileIdTyped(
+	p GetFileByFileIdXQuery,
+) (*FilesResponse, error) {
+	return c.GetFileByFileIdTypedWithContext(context.Background(), p)
+}
+
+// GetFileByFileIdTypedWithContext is like GetFileByFileIdTyped but binds the request to ctx.
+func (c *Assets) GetFileByFileIdTypedWithContext(
+	ctx context.Context,
+	p GetFileByFileIdXQuery,
+) (*FilesResponse, error) {
+	resp := &FilesResponse{}
+	err := c.getFileByFileIdRequest(p).executeInto(ctx, resp)
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (c *Assets) getFileByFileIdRequest(
+	p GetFileByFileIdXQuery,
+) *APIClient {
 
 	queryParams := make(map[string]string)
 
-	apiClient := &APIClient{
+	return &APIClient{
 		Conf:        c.config,
 		Method:      "get",
 		Url:         fmt.Sprintf("/service/platform/assets/v1.0/files/%s", p.FileId),
@@ -254,17 +406,6 @@ func (c *Assets) GetFileByFileId(
 		ContentType: "",
 	}
 
-	response, err := apiClient.Execute()
-	if err != nil {
-		return nil, err
-	}
-	resp := map[string]interface{}{}
-	err = json.Unmarshal(response, &resp)
-	if err != nil {
-		return nil, common.NewFDKError(err.Error())
-	}
-	return resp, nil
-
 }
 
 type UpdateFileXQuery struct {
@@ -287,6 +428,45 @@ params: UpdateFileXQuery
 func (c *Assets) UpdateFile(
 	p UpdateFileXQuery,
 ) (map[string]interface{}, error) {
+	return c.UpdateFileWithContext(context.Background(), p)
+}
+
+// UpdateFileWithContext is like UpdateFile but binds the request to ctx.
+func (c *Assets) UpdateFileWithContext(
+	ctx context.Context,
+	p UpdateFileXQuery,
+) (map[string]interface{}, error) {
+	resp := map[string]interface{}{}
+	err := c.updateFileRequest(p).executeInto(ctx, &resp)
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// UpdateFileTyped is like UpdateFile but decodes the response into FilesResponse.
+func (c *Assets) UpdateFileTyped(
+	p UpdateFileXQuery,
+) (*FilesResponse, error) {
+	return c.UpdateFileTypedWithContext(context.Background(), p)
+}
+
+// UpdateFileTypedWithContext is like UpdateFileTyped but binds the request to ctx.
+func (c *Assets) UpdateFileTypedWithContext(
+	ctx context.Context,
+	p UpdateFileXQuery,
+) (*FilesResponse, error) {
+	resp := &FilesResponse{}
+	err := c.updateFileRequest(p).executeInto(ctx, resp)
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (c *Assets) updateFileRequest(
+	p UpdateFileXQuery,
+) *APIClient {
 
 	type body struct {
 		Name string `json:"name,omitempty"`
@@ -318,7 +498,7 @@ func (c *Assets) UpdateFile(
 
 	queryParams := make(map[string]string)
 
-	apiClient := &APIClient{
+	return &APIClient{
 		Conf:        c.config,
 		Method:      "patch",
 		Url:         fmt.Sprintf("/service/platform/assets/v1.0/files/%s", p.FileId),
@@ -327,17 +507,6 @@ func (c *Assets) UpdateFile(
 		ContentType: "application/json",
 	}
 
-	response, err := apiClient.Execute()
-	if err != nil {
-		return nil, err
-	}
-	resp := map[string]interface{}{}
-	err = json.Unmarshal(response, &resp)
-	if err != nil {
-		return nil, common.NewFDKError(err.Error())
-	}
-	return resp, nil
-
 }
 
 type DeleteFileXQuery struct {
@@ -354,10 +523,49 @@ params: DeleteFileXQuery
 func (c *Assets) DeleteFile(
 	p DeleteFileXQuery,
 ) (map[string]interface{}, error) {
+	return c.DeleteFileWithContext(context.Background(), p)
+}
+
+// DeleteFileWithContext is like DeleteFile but binds the request to ctx.
+func (c *Assets) DeleteFileWithContext(
+	ctx context.Context,
+	p DeleteFileXQuery,
+) (map[string]interface{}, error) {
+	resp := map[string]interface{}{}
+	err := c.deleteFileRequest(p).executeInto(ctx, &resp)
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// DeleteFileTyped is like DeleteFile but decodes the response into FilesResponse.
+func (c *Assets) DeleteFileTyped(
+	p DeleteFileXQuery,
+) (*FilesResponse, error) {
+	return c.DeleteFileTypedWithContext(context.Background(), p)
+}
+
+// DeleteFileTypedWithContext is like DeleteFileTyped but binds the request to ctx.
+func (c *Assets) DeleteFileTypedWithContext(
+	ctx context.Context,
+	p DeleteFileXQuery,
+) (*FilesResponse, error) {
+	resp := &FilesResponse{}
+	err := c.deleteFileRequest(p).executeInto(ctx, resp)
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (c *Assets) deleteFileRequest(
+	p DeleteFileXQuery,
+) *APIClient {
 
 	queryParams := make(map[string]string)
 
-	apiClient := &APIClient{
+	return &APIClient{
 		Conf:        c.config,
 		Method:      "delete",
 		Url:         fmt.Sprintf("/service/platform/assets/v1.0/files/%s", p.FileId),
@@ -366,17 +574,6 @@ func (c *Assets) DeleteFile(
 		ContentType: "",
 	}
 
-	response, err := apiClient.Execute()
-	if err != nil {
-		return nil, err
-	}
-	resp := map[string]interface{}{}
-	err = json.Unmarshal(response, &resp)
-	if err != nil {
-		return nil, common.NewFDKError(err.Error())
-	}
-	return resp, nil
-
 }
 
 type DeleteFilesXQuery struct {
@@ -393,6 +590,45 @@ params: DeleteFilesXQuery
 func (c *Assets) DeleteFiles(
 	p DeleteFilesXQuery,
 ) (map[string]interface{}, error) {
+	return c.DeleteFilesWithContext(context.Background(), p)
+}
+
+// DeleteFilesWithContext is like DeleteFiles but binds the request to ctx.
+func (c *Assets) DeleteFilesWithContext(
+	ctx context.Context,
+	p DeleteFilesXQuery,
+) (map[string]interface{}, error) {
+	resp := map[string]interface{}{}
+	err := c.deleteFilesRequest(p).executeInto(ctx, &resp)
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// DeleteFilesTyped is like DeleteFiles but decodes the response into []FilesResponse.
+func (c *Assets) DeleteFilesTyped(
+	p DeleteFilesXQuery,
+) ([]FilesResponse, error) {
+	return c.DeleteFilesTypedWithContext(context.Background(), p)
+}
+
+// DeleteFilesTypedWithContext is like DeleteFilesTyped but binds the request to ctx.
+func (c *Assets) DeleteFilesTypedWithContext(
+	ctx context.Context,
+	p DeleteFilesXQuery,
+) ([]FilesResponse, error) {
+	var resp []FilesResponse
+	err := c.deleteFilesRequest(p).executeInto(ctx, &resp)
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (c *Assets) deleteFilesRequest(
+	p DeleteFilesXQuery,
+) *APIClient {
 
 	type body struct {
 		Ids []string `json:"ids,omitempty"`
@@ -404,7 +640,7 @@ func (c *Assets) DeleteFiles(
 
 	queryParams := make(map[string]string)
 
-	apiClient := &APIClient{
+	return &APIClient{
 		Conf:        c.config,
 		Method:      "post",
 		Url:         "/service/platform/assets/v1.0/files/delete",
@@ -413,17 +649,6 @@ func (c *Assets) DeleteFiles(
 		ContentType: "application/json",
 	}
 
-	response, err := apiClient.Execute()
-	if err != nil {
-		return nil, err
-	}
-	resp := map[string]interface{}{}
-	err = json.Unmarshal(response, &resp)
-	if err != nil {
-		return nil, common.NewFDKError(err.Error())
-	}
-	return resp, nil
-
 }
 
 type CreateFolderXQuery struct {
@@ -441,22 +666,61 @@ params: CreateFolderXQuery
 func (c *Assets) CreateFolder(
 	p CreateFolderXQuery,
 ) (map[string]interface{}, error) {
+	return c.CreateFolderWithContext(context.Background(), p)
+}
 
-	type body struct {
-		Name string `json:"name,omitempty"`
-
-		Path string `json:"path,omitempty"`
+// CreateFolderWithContext is like CreateFolder but binds the request to ctx.
+func (c *Assets) CreateFolderWithContext(
+	ctx context.Context,
+	p CreateFolderXQuery,
+) (map[string]interface{}, error) {
+	resp := map[string]interface{}{}
+	err := c.createFolderRequest(p).executeInto(ctx, &resp)
+	if err != nil {
+		return nil, err
 	}
-	bodydata := &body{
+	return resp, nil
+}
 
-		Name: p.Name,
+// CreateFolderTyped is like CreateFolder but decodes the response into FoldersResponse.
+func (c *Assets) CreateFolderTyped(
+	p CreateFolderXQuery,
+) (*FoldersResponse, error) {
+	return c.CreateFolderTypedWithContext(context.Background(), p)
+}
+
+// CreateFolderTypedWithContext is like CreateFolderTyped but binds the request to ctx.
+func (c *Assets) CreateFolderTypedWithContext(
+	ctx context.Context,
+	p CreateFolderXQuery,
+) (*FoldersResponse, error) {
+	resp := &FoldersResponse{}
+	err := c.createFolderRequest(p).executeInto(ctx, resp)
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (c *Assets) createFolderRequest(
+	p CreateFolderXQuery,
+) *APIClient {
+
+	type body struct {
+		Name string `json:"name,omitempty"`
+
+		Path string `json:"path,omitempty"`
+	}
+	bodydata := &body{
+
+		Name: p.Name,
 
 		Path: p.Path,
 	}
 
 	queryParams := make(map[string]string)
 
-	apiClient := &APIClient{
+	return &APIClient{
 		Conf:        c.config,
 		Method:      "post",
 		Url:         "/service/platform/assets/v1.0/folders",
@@ -465,17 +729,6 @@ func (c *Assets) CreateFolder(
 		ContentType: "application/json",
 	}
 
-	response, err := apiClient.Execute()
-	if err != nil {
-		return nil, err
-	}
-	resp := map[string]interface{}{}
-	err = json.Unmarshal(response, &resp)
-	if err != nil {
-		return nil, common.NewFDKError(err.Error())
-	}
-	return resp, nil
-
 }
 
 type GetFolderDetailsXQuery struct {
@@ -493,6 +746,45 @@ params: GetFolderDetailsXQuery
 func (c *Assets) GetFolderDetails(
 	p GetFolderDetailsXQuery,
 ) (map[string]interface{}, error) {
+	return c.GetFolderDetailsWithContext(context.Background(), p)
+}
+
+// GetFolderDetailsWithContext is like GetFolderDetails but binds the request to ctx.
+func (c *Assets) GetFolderDetailsWithContext(
+	ctx context.Context,
+	p GetFolderDetailsXQuery,
+) (map[string]interface{}, error) {
+	resp := map[string]interface{}{}
+	err := c.getFolderDetailsRequest(p).executeInto(ctx, &resp)
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// GetFolderDetailsTyped is like GetFolderDetails but decodes the response into []ExploreItem.
+func (c *Assets) GetFolderDetailsTyped(
+	p GetFolderDetailsXQuery,
+) ([]ExploreItem, error) {
+	return c.GetFolderDetailsTypedWithContext(context.Background(), p)
+}
+
+// GetFolderDetailsTypedWithContext is like GetFolderDetailsTyped but binds the request to ctx.
+func (c *Assets) GetFolderDetailsTypedWithContext(
+	ctx context.Context,
+	p GetFolderDetailsXQuery,
+) ([]ExploreItem, error) {
+	var resp []ExploreItem
+	err := c.getFolderDetailsRequest(p).executeInto(ctx, &resp)
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (c *Assets) getFolderDetailsRequest(
+	p GetFolderDetailsXQuery,
+) *APIClient {
 
 	queryParams := make(map[string]string)
 
@@ -504,7 +796,7 @@ func (c *Assets) GetFolderDetails(
 		queryParams["name"] = fmt.Sprintf("%v", p.Name)
 	}
 
-	apiClient := &APIClient{
+	return &APIClient{
 		Conf:        c.config,
 		Method:      "get",
 		Url:         "/service/platform/assets/v1.0/folders",
@@ -513,17 +805,6 @@ func (c *Assets) GetFolderDetails(
 		ContentType: "",
 	}
 
-	response, err := apiClient.Execute()
-	if err != nil {
-		return nil, err
-	}
-	resp := map[string]interface{}{}
-	err = json.Unmarshal(response, &resp)
-	if err != nil {
-		return nil, common.NewFDKError(err.Error())
-	}
-	return resp, nil
-
 }
 
 type UpdateFolderXQuery struct {
@@ -543,6 +824,45 @@ params: UpdateFolderXQuery
 func (c *Assets) UpdateFolder(
 	p UpdateFolderXQuery,
 ) (map[string]interface{}, error) {
+	return c.UpdateFolderWithContext(context.Background(), p)
+}
+
+// UpdateFolderWithContext is like UpdateFolder but binds the request to ctx.
+func (c *Assets) UpdateFolderWithContext(
+	ctx context.Context,
+	p UpdateFolderXQuery,
+) (map[string]interface{}, error) {
+	resp := map[string]interface{}{}
+	err := c.updateFolderRequest(p).executeInto(ctx, &resp)
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// UpdateFolderTyped is like UpdateFolder but decodes the response into FoldersResponse.
+func (c *Assets) UpdateFolderTyped(
+	p UpdateFolderXQuery,
+) (*FoldersResponse, error) {
+	return c.UpdateFolderTypedWithContext(context.Background(), p)
+}
+
+// UpdateFolderTypedWithContext is like UpdateFolderTyped but binds the request to ctx.
+func (c *Assets) UpdateFolderTypedWithContext(
+	ctx context.Context,
+	p UpdateFolderXQuery,
+) (*FoldersResponse, error) {
+	resp := &FoldersResponse{}
+	err := c.updateFolderRequest(p).executeInto(ctx, resp)
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (c *Assets) updateFolderRequest(
+	p UpdateFolderXQuery,
+) *APIClient {
 
 	type body struct {
 		IsActive bool `json:"isActive,omitempty"`
@@ -554,7 +874,7 @@ func (c *Assets) UpdateFolder(
 
 	queryParams := make(map[string]string)
 
-	apiClient := &APIClient{
+	return &APIClient{
 		Conf:        c.config,
 		Method:      "patch",
 		Url:         fmt.Sprintf("/service/platform/assets/v1.0/folders/%s", p.FolderId),
@@ -563,17 +883,6 @@ func (c *Assets) UpdateFolder(
 		ContentType: "application/json",
 	}
 
-	response, err := apiClient.Execute()
-	if err != nil {
-		return nil, err
-	}
-	resp := map[string]interface{}{}
-	err = json.Unmarshal(response, &resp)
-	if err != nil {
-		return nil, common.NewFDKError(err.Error())
-	}
-	return resp, nil
-
 }
 
 type DeleteFolderXQuery struct {
@@ -590,10 +899,49 @@ params: DeleteFolderXQuery
 func (c *Assets) DeleteFolder(
 	p DeleteFolderXQuery,
 ) (map[string]interface{}, error) {
+	return c.DeleteFolderWithContext(context.Background(), p)
+}
+
+// DeleteFolderWithContext is like DeleteFolder but binds the request to ctx.
+func (c *Assets) DeleteFolderWithContext(
+	ctx context.Context,
+	p DeleteFolderXQuery,
+) (map[string]interface{}, error) {
+	resp := map[string]interface{}{}
+	err := c.deleteFolderRequest(p).executeInto(ctx, &resp)
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// DeleteFolderTyped is like DeleteFolder but decodes the response into FoldersResponse.
+func (c *Assets) DeleteFolderTyped(
+	p DeleteFolderXQuery,
+) (*FoldersResponse, error) {
+	return c.DeleteFolderTypedWithContext(context.Background(), p)
+}
+
+// DeleteFolderTypedWithContext is like DeleteFolderTyped but binds the request to ctx.
+func (c *Assets) DeleteFolderTypedWithContext(
+	ctx context.Context,
+	p DeleteFolderXQuery,
+) (*FoldersResponse, error) {
+	resp := &FoldersResponse{}
+	err := c.deleteFolderRequest(p).executeInto(ctx, resp)
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (c *Assets) deleteFolderRequest(
+	p DeleteFolderXQuery,
+) *APIClient {
 
 	queryParams := make(map[string]string)
 
-	apiClient := &APIClient{
+	return &APIClient{
 		Conf:        c.config,
 		Method:      "delete",
 		Url:         fmt.Sprintf("/service/platform/assets/v1.0/folders/%s", p.ID),
@@ -602,17 +950,6 @@ func (c *Assets) DeleteFolder(
 		ContentType: "",
 	}
 
-	response, err := apiClient.Execute()
-	if err != nil {
-		return nil, err
-	}
-	resp := map[string]interface{}{}
-	err = json.Unmarshal(response, &resp)
-	if err != nil {
-		return nil, common.NewFDKError(err.Error())
-	}
-	return resp, nil
-
 }
 
 type GetFolderAncestorsXQuery struct {
@@ -629,10 +966,49 @@ params: GetFolderAncestorsXQuery
 func (c *Assets) GetFolderAncestors(
 	p GetFolderAncestorsXQuery,
 ) (map[string]interface{}, error) {
+	return c.GetFolderAncestorsWithContext(context.Background(), p)
+}
+
+// GetFolderAncestorsWithContext is like GetFolderAncestors but binds the request to ctx.
+func (c *Assets) GetFolderAncestorsWithContext(
+	ctx context.Context,
+	p GetFolderAncestorsXQuery,
+) (map[string]interface{}, error) {
+	resp := map[string]interface{}{}
+	err := c.getFolderAncestorsRequest(p).executeInto(ctx, &resp)
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// GetFolderAncestorsTyped is like GetFolderAncestors but decodes the response into GetAncestorsResponse.
+func (c *Assets) GetFolderAncestorsTyped(
+	p GetFolderAncestorsXQuery,
+) (*GetAncestorsResponse, error) {
+	return c.GetFolderAncestorsTypedWithContext(context.Background(), p)
+}
+
+// GetFolderAncestorsTypedWithContext is like GetFolderAncestorsTyped but binds the request to ctx.
+func (c *Assets) GetFolderAncestorsTypedWithContext(
+	ctx context.Context,
+	p GetFolderAncestorsXQuery,
+) (*GetAncestorsResponse, error) {
+	resp := &GetAncestorsResponse{}
+	err := c.getFolderAncestorsRequest(p).executeInto(ctx, resp)
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (c *Assets) getFolderAncestorsRequest(
+	p GetFolderAncestorsXQuery,
+) *APIClient {
 
 	queryParams := make(map[string]string)
 
-	apiClient := &APIClient{
+	return &APIClient{
 		Conf:        c.config,
 		Method:      "get",
 		Url:         fmt.Sprintf("/service/platform/assets/v1.0/folders/%s/ancestors", p.ID),
@@ -641,17 +1017,6 @@ func (c *Assets) GetFolderAncestors(
 		ContentType: "",
 	}
 
-	response, err := apiClient.Execute()
-	if err != nil {
-		return nil, err
-	}
-	resp := map[string]interface{}{}
-	err = json.Unmarshal(response, &resp)
-	if err != nil {
-		return nil, common.NewFDKError(err.Error())
-	}
-	return resp, nil
-
 }
 
 type ListFilesXQuery struct {
@@ -676,8 +1041,48 @@ params: ListFilesXQuery
 func (c *Assets) ListFiles(
 	p ListFilesXQuery,
 ) (map[string]interface{}, error) {
+	return c.ListFilesWithContext(context.Background(), p)
+}
+
+// ListFilesWithContext is like ListFiles but binds the request to ctx.
+func (c *Assets) ListFilesWithContext(
+	ctx context.Context,
+	p ListFilesXQuery,
+) (map[string]interface{}, error) {
+	resp := map[string]interface{}{}
+	err := c.listFilesRequest(p).executeInto(ctx, &resp)
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// ListFilesTyped is like ListFiles but decodes the response into ListFilesResponse.
+func (c *Assets) ListFilesTyped(
+	p ListFilesXQuery,
+) (*ListFilesResponse, error) {
+	return c.ListFilesTypedWithContext(context.Background(), p)
+}
+
+// ListFilesTypedWithContext is like ListFilesTyped but binds the request to ctx.
+func (c *Assets) ListFilesTypedWithContext(
+	ctx context.Context,
+	p ListFilesXQuery,
+) (*ListFilesResponse, error) {
+	resp := &ListFilesResponse{}
+	err := c.listFilesRequest(p).executeInto(ctx, resp)
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (c *Assets) listFilesRequest(
+	p ListFilesXQuery,
+) *APIClient {
 
 	queryParams := make(map[string]string)
+	queryValues := url.Values{}
 
 	if p.Name != "" {
 		queryParams["name"] = fmt.Sprintf("%v", p.Name)
@@ -691,8 +1096,8 @@ func (c *Assets) ListFiles(
 		queryParams["format"] = fmt.Sprintf("%v", p.Format)
 	}
 
-	if p.Tags != nil {
-		queryParams["tags"] = fmt.Sprintf("%v", p.Tags)
+	for _, v := range p.Tags {
+		queryValues.Add("tags", fmt.Sprintf("%v", v))
 	}
 
 	if p.OnlyFiles != false {
@@ -715,26 +1120,16 @@ func (c *Assets) ListFiles(
 		queryParams["sort"] = fmt.Sprintf("%v", p.Sort)
 	}
 
-	apiClient := &APIClient{
+	return &APIClient{
 		Conf:        c.config,
 		Method:      "get",
 		Url:         "/service/platform/assets/v1.0/listFiles",
 		Query:       queryParams,
+		QueryValues: queryValues,
 		Body:        nil,
 		ContentType: "",
 	}
 
-	response, err := apiClient.Execute()
-	if err != nil {
-		return nil, err
-	}
-	resp := map[string]interface{}{}
-	err = json.Unmarshal(response, &resp)
-	if err != nil {
-		return nil, common.NewFDKError(err.Error())
-	}
-	return resp, nil
-
 }
 
 type GetDefaultAssetForPlaygroundXQuery struct {
@@ -750,10 +1145,49 @@ params: GetDefaultAssetForPlaygroundXQuery
 func (c *Assets) GetDefaultAssetForPlayground(
 	p GetDefaultAssetForPlaygroundXQuery,
 ) (map[string]interface{}, error) {
+	return c.GetDefaultAssetForPlaygroundWithContext(context.Background(), p)
+}
+
+// GetDefaultAssetForPlaygroundWithContext is like GetDefaultAssetForPlayground but binds the request to ctx.
+func (c *Assets) GetDefaultAssetForPlaygroundWithContext(
+	ctx context.Context,
+	p GetDefaultAssetForPlaygroundXQuery,
+) (map[string]interface{}, error) {
+	resp := map[string]interface{}{}
+	err := c.getDefaultAssetForPlaygroundRequest(p).executeInto(ctx, &resp)
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// GetDefaultAssetForPlaygroundTyped is like GetDefaultAssetForPlayground but decodes the response into UploadResponse.
+func (c *Assets) GetDefaultAssetForPlaygroundTyped(
+	p GetDefaultAssetForPlaygroundXQuery,
+) (*UploadResponse, error) {
+	return c.GetDefaultAssetForPlaygroundTypedWithContext(context.Background(), p)
+}
+
+// GetDefaultAssetForPlaygroundTypedWithContext is like GetDefaultAssetForPlaygroundTyped but binds the request to ctx.
+func (c *Assets) GetDefaultAssetForPlaygroundTypedWithContext(
+	ctx context.Context,
+	p GetDefaultAssetForPlaygroundXQuery,
+) (*UploadResponse, error) {
+	resp := &UploadResponse{}
+	err := c.getDefaultAssetForPlaygroundRequest(p).executeInto(ctx, resp)
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (c *Assets) getDefaultAssetForPlaygroundRequest(
+	p GetDefaultAssetForPlaygroundXQuery,
+) *APIClient {
 
 	queryParams := make(map[string]string)
 
-	apiClient := &APIClient{
+	return &APIClient{
 		Conf:        c.config,
 		Method:      "get",
 		Url:         "/service/platform/assets/v1.0/playground/default",
@@ -762,17 +1196,6 @@ func (c *Assets) GetDefaultAssetForPlayground(
 		ContentType: "",
 	}
 
-	response, err := apiClient.Execute()
-	if err != nil {
-		return nil, err
-	}
-	resp := map[string]interface{}{}
-	err = json.Unmarshal(response, &resp)
-	if err != nil {
-		return nil, common.NewFDKError(err.Error())
-	}
-	return resp, nil
-
 }
 
 type GetModulesXQuery struct {
@@ -788,10 +1211,49 @@ params: GetModulesXQuery
 func (c *Assets) GetModules(
 	p GetModulesXQuery,
 ) (map[string]interface{}, error) {
+	return c.GetModulesWithContext(context.Background(), p)
+}
+
+// GetModulesWithContext is like GetModules but binds the request to ctx.
+func (c *Assets) GetModulesWithContext(
+	ctx context.Context,
+	p GetModulesXQuery,
+) (map[string]interface{}, error) {
+	resp := map[string]interface{}{}
+	err := c.getModulesRequest(p).executeInto(ctx, &resp)
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// GetModulesTyped is like GetModules but decodes the response into TransformationModulesResponse.
+func (c *Assets) GetModulesTyped(
+	p GetModulesXQuery,
+) (*TransformationModulesResponse, error) {
+	return c.GetModulesTypedWithContext(context.Background(), p)
+}
+
+// GetModulesTypedWithContext is like GetModulesTyped but binds the request to ctx.
+func (c *Assets) GetModulesTypedWithContext(
+	ctx context.Context,
+	p GetModulesXQuery,
+) (*TransformationModulesResponse, error) {
+	resp := &TransformationModulesResponse{}
+	err := c.getModulesRequest(p).executeInto(ctx, resp)
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (c *Assets) getModulesRequest(
+	p GetModulesXQuery,
+) *APIClient {
 
 	queryParams := make(map[string]string)
 
-	apiClient := &APIClient{
+	return &APIClient{
 		Conf:        c.config,
 		Method:      "get",
 		Url:         "/service/platform/assets/v1.0/playground/plugins",
@@ -800,17 +1262,6 @@ func (c *Assets) GetModules(
 		ContentType: "",
 	}
 
-	response, err := apiClient.Execute()
-	if err != nil {
-		return nil, err
-	}
-	resp := map[string]interface{}{}
-	err = json.Unmarshal(response, &resp)
-	if err != nil {
-		return nil, common.NewFDKError(err.Error())
-	}
-	return resp, nil
-
 }
 
 type GetModuleXQuery struct {
@@ -827,32 +1278,60 @@ params: GetModuleXQuery
 func (c *Assets) GetModule(
 	p GetModuleXQuery,
 ) (map[string]interface{}, error) {
+	return c.GetModuleWithContext(context.Background(), p)
+}
 
-	queryParams := make(map[string]string)
-
-	apiClient := &APIClient{
-		Conf:        c.config,
-		Method:      "get",
-		Url:         fmt.Sprintf("/service/platform/assets/v1.0/playground/plugins/%s", p.Identifier),
-		Query:       queryParams,
-		Body:        nil,
-		ContentType: "",
-	}
-
-	response, err := apiClient.Execute()
-	if err != nil {
-		return nil, err
-	}
+// GetModuleWithContext is like GetModule but binds the request to ctx.
+func (c *Assets) GetModuleWithContext(
+	ctx context.Context,
+	p GetModuleXQuery,
+) (map[string]interface{}, error) {
 	resp := map[string]interface{}{}
-	err = json.Unmarshal(response, &resp)
+	err := c.getModuleRequest(p).executeInto(ctx, &resp)
 	if err != nil {
-		return nil, common.NewFDKError(err.Error())
+		return nil, err
 	}
 	return resp, nil
+}
 
+// GetModuleTyped is like GetModule but decodes the response into TransformationModuleResponse.
+func (c *Assets) GetModuleTyped(
+	p GetModuleXQuery,
+) (*TransformationModuleResponse, error) {
+	return c.GetModuleTypedWithContext(context.Background(), p)
 }
 
-type AddPresetXQuery struct {
+// GetModuleTypedWithContext is like GetModuleTyped but binds the request to ctx.
+func (c *Assets) GetModuleTypedWithContext(
+	ctx context.Context,
+	p GetModuleXQuery,
+) (*TransformationModuleResponse, error) {
+	resp := &TransformationModuleResponse{}
+	err := c.getModuleRequest(p).executeInto(ctx, resp)
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (c *Assets) getModuleRequest(
+	p GetModuleXQuery,
+) *APIClient {
+
+	queryParams := make(map[string]string)
+
+	return &APIClient{
+		Conf:        c.config,
+		Method:      "get",
+		Url:         fmt.Sprintf("/service/platform/assets/v1.0/playground/plugins/%s", p.Identifier),
+		Query:       queryParams,
+		Body:        nil,
+		ContentType: "",
+	}
+
+}
+
+type AddPresetXQuery struct {
 	PresetName     string                 `json:"presetName,omitempty"`
 	Transformation string                 `json:"transformation,omitempty"`
 	Params         map[string]interface{} `json:"params,omitempty"`
@@ -868,6 +1347,45 @@ params: AddPresetXQuery
 func (c *Assets) AddPreset(
 	p AddPresetXQuery,
 ) (map[string]interface{}, error) {
+	return c.AddPresetWithContext(context.Background(), p)
+}
+
+// AddPresetWithContext is like AddPreset but binds the request to ctx.
+func (c *Assets) AddPresetWithContext(
+	ctx context.Context,
+	p AddPresetXQuery,
+) (map[string]interface{}, error) {
+	resp := map[string]interface{}{}
+	err := c.addPresetRequest(p).executeInto(ctx, &resp)
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// AddPresetTyped is like AddPreset but decodes the response into AddPresetResponse.
+func (c *Assets) AddPresetTyped(
+	p AddPresetXQuery,
+) (*AddPresetResponse, error) {
+	return c.AddPresetTypedWithContext(context.Background(), p)
+}
+
+// AddPresetTypedWithContext is like AddPresetTyped but binds the request to ctx.
+func (c *Assets) AddPresetTypedWithContext(
+	ctx context.Context,
+	p AddPresetXQuery,
+) (*AddPresetResponse, error) {
+	resp := &AddPresetResponse{}
+	err := c.addPresetRequest(p).executeInto(ctx, resp)
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (c *Assets) addPresetRequest(
+	p AddPresetXQuery,
+) *APIClient {
 
 	type body struct {
 		PresetName string `json:"presetName,omitempty"`
@@ -887,7 +1405,7 @@ func (c *Assets) AddPreset(
 
 	queryParams := make(map[string]string)
 
-	apiClient := &APIClient{
+	return &APIClient{
 		Conf:        c.config,
 		Method:      "post",
 		Url:         "/service/platform/assets/v1.0/presets",
@@ -896,17 +1414,6 @@ func (c *Assets) AddPreset(
 		ContentType: "application/json",
 	}
 
-	response, err := apiClient.Execute()
-	if err != nil {
-		return nil, err
-	}
-	resp := map[string]interface{}{}
-	err = json.Unmarshal(response, &resp)
-	if err != nil {
-		return nil, common.NewFDKError(err.Error())
-	}
-	return resp, nil
-
 }
 
 type GetPresetsXQuery struct {
@@ -928,8 +1435,48 @@ params: GetPresetsXQuery
 func (c *Assets) GetPresets(
 	p GetPresetsXQuery,
 ) (map[string]interface{}, error) {
+	return c.GetPresetsWithContext(context.Background(), p)
+}
+
+// GetPresetsWithContext is like GetPresets but binds the request to ctx.
+func (c *Assets) GetPresetsWithContext(
+	ctx context.Context,
+	p GetPresetsXQuery,
+) (map[string]interface{}, error) {
+	resp := map[string]interface{}{}
+	err := c.getPresetsRequest(p).executeInto(ctx, &resp)
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// GetPresetsTyped is like GetPresets but decodes the response into GetPresetsResponse.
+func (c *Assets) GetPresetsTyped(
+	p GetPresetsXQuery,
+) (*GetPresetsResponse, error) {
+	return c.GetPresetsTypedWithContext(context.Background(), p)
+}
+
+// GetPresetsTypedWithContext is like GetPresetsTyped but binds the request to ctx.
+func (c *Assets) GetPresetsTypedWithContext(
+	ctx context.Context,
+	p GetPresetsXQuery,
+) (*GetPresetsResponse, error) {
+	resp := &GetPresetsResponse{}
+	err := c.getPresetsRequest(p).executeInto(ctx, resp)
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (c *Assets) getPresetsRequest(
+	p GetPresetsXQuery,
+) *APIClient {
 
 	queryParams := make(map[string]string)
+	queryValues := url.Values{}
 
 	if p.PageNo != 0 {
 		queryParams["pageNo"] = fmt.Sprintf("%v", p.PageNo)
@@ -951,30 +1498,20 @@ func (c *Assets) GetPresets(
 		queryParams["archived"] = fmt.Sprintf("%v", p.Archived)
 	}
 
-	if p.Sort != nil {
-		queryParams["sort"] = fmt.Sprintf("%v", p.Sort)
+	for _, v := range p.Sort {
+		queryValues.Add("sort", fmt.Sprintf("%v", v))
 	}
 
-	apiClient := &APIClient{
+	return &APIClient{
 		Conf:        c.config,
 		Method:      "get",
 		Url:         "/service/platform/assets/v1.0/presets",
 		Query:       queryParams,
+		QueryValues: queryValues,
 		Body:        nil,
 		ContentType: "",
 	}
 
-	response, err := apiClient.Execute()
-	if err != nil {
-		return nil, err
-	}
-	resp := map[string]interface{}{}
-	err = json.Unmarshal(response, &resp)
-	if err != nil {
-		return nil, common.NewFDKError(err.Error())
-	}
-	return resp, nil
-
 }
 
 type UpdatePresetXQuery struct {
@@ -992,6 +1529,45 @@ params: UpdatePresetXQuery
 func (c *Assets) UpdatePreset(
 	p UpdatePresetXQuery,
 ) (map[string]interface{}, error) {
+	return c.UpdatePresetWithContext(context.Background(), p)
+}
+
+// UpdatePresetWithContext is like UpdatePreset but binds the request to ctx.
+func (c *Assets) UpdatePresetWithContext(
+	ctx context.Context,
+	p UpdatePresetXQuery,
+) (map[string]interface{}, error) {
+	resp := map[string]interface{}{}
+	err := c.updatePresetRequest(p).executeInto(ctx, &resp)
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// UpdatePresetTyped is like UpdatePreset but decodes the response into AddPresetResponse.
+func (c *Assets) UpdatePresetTyped(
+	p UpdatePresetXQuery,
+) (*AddPresetResponse, error) {
+	return c.UpdatePresetTypedWithContext(context.Background(), p)
+}
+
+// UpdatePresetTypedWithContext is like UpdatePresetTyped but binds the request to ctx.
+func (c *Assets) UpdatePresetTypedWithContext(
+	ctx context.Context,
+	p UpdatePresetXQuery,
+) (*AddPresetResponse, error) {
+	resp := &AddPresetResponse{}
+	err := c.updatePresetRequest(p).executeInto(ctx, resp)
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (c *Assets) updatePresetRequest(
+	p UpdatePresetXQuery,
+) *APIClient {
 
 	type body struct {
 		Archived bool `json:"archived,omitempty"`
@@ -1003,7 +1579,7 @@ func (c *Assets) UpdatePreset(
 
 	queryParams := make(map[string]string)
 
-	apiClient := &APIClient{
+	return &APIClient{
 		Conf:        c.config,
 		Method:      "patch",
 		Url:         fmt.Sprintf("/service/platform/assets/v1.0/presets/%s", p.PresetName),
@@ -1012,17 +1588,6 @@ func (c *Assets) UpdatePreset(
 		ContentType: "application/json",
 	}
 
-	response, err := apiClient.Execute()
-	if err != nil {
-		return nil, err
-	}
-	resp := map[string]interface{}{}
-	err = json.Unmarshal(response, &resp)
-	if err != nil {
-		return nil, common.NewFDKError(err.Error())
-	}
-	return resp, nil
-
 }
 
 type DeletePresetXQuery struct {
@@ -1039,10 +1604,49 @@ params: DeletePresetXQuery
 func (c *Assets) DeletePreset(
 	p DeletePresetXQuery,
 ) (map[string]interface{}, error) {
+	return c.DeletePresetWithContext(context.Background(), p)
+}
+
+// DeletePresetWithContext is like DeletePreset but binds the request to ctx.
+func (c *Assets) DeletePresetWithContext(
+	ctx context.Context,
+	p DeletePresetXQuery,
+) (map[string]interface{}, error) {
+	resp := map[string]interface{}{}
+	err := c.deletePresetRequest(p).executeInto(ctx, &resp)
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// DeletePresetTyped is like DeletePreset but decodes the response into AddPresetResponse.
+func (c *Assets) DeletePresetTyped(
+	p DeletePresetXQuery,
+) (*AddPresetResponse, error) {
+	return c.DeletePresetTypedWithContext(context.Background(), p)
+}
+
+// DeletePresetTypedWithContext is like DeletePresetTyped but binds the request to ctx.
+func (c *Assets) DeletePresetTypedWithContext(
+	ctx context.Context,
+	p DeletePresetXQuery,
+) (*AddPresetResponse, error) {
+	resp := &AddPresetResponse{}
+	err := c.deletePresetRequest(p).executeInto(ctx, resp)
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (c *Assets) deletePresetRequest(
+	p DeletePresetXQuery,
+) *APIClient {
 
 	queryParams := make(map[string]string)
 
-	apiClient := &APIClient{
+	return &APIClient{
 		Conf:        c.config,
 		Method:      "delete",
 		Url:         fmt.Sprintf("/service/platform/assets/v1.0/presets/%s", p.PresetName),
@@ -1051,17 +1655,6 @@ func (c *Assets) DeletePreset(
 		ContentType: "",
 	}
 
-	response, err := apiClient.Execute()
-	if err != nil {
-		return nil, err
-	}
-	resp := map[string]interface{}{}
-	err = json.Unmarshal(response, &resp)
-	if err != nil {
-		return nil, common.NewFDKError(err.Error())
-	}
-	return resp, nil
-
 }
 
 type GetPresetXQuery struct {
@@ -1078,10 +1671,49 @@ params: GetPresetXQuery
 func (c *Assets) GetPreset(
 	p GetPresetXQuery,
 ) (map[string]interface{}, error) {
+	return c.GetPresetWithContext(context.Background(), p)
+}
+
+// GetPresetWithContext is like GetPreset but binds the request to ctx.
+func (c *Assets) GetPresetWithContext(
+	ctx context.Context,
+	p GetPresetXQuery,
+) (map[string]interface{}, error) {
+	resp := map[string]interface{}{}
+	err := c.getPresetRequest(p).executeInto(ctx, &resp)
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// GetPresetTyped is like GetPreset but decodes the response into AddPresetResponse.
+func (c *Assets) GetPresetTyped(
+	p GetPresetXQuery,
+) (*AddPresetResponse, error) {
+	return c.GetPresetTypedWithContext(context.Background(), p)
+}
+
+// GetPresetTypedWithContext is like GetPresetTyped but binds the request to ctx.
+func (c *Assets) GetPresetTypedWithContext(
+	ctx context.Context,
+	p GetPresetXQuery,
+) (*AddPresetResponse, error) {
+	resp := &AddPresetResponse{}
+	err := c.getPresetRequest(p).executeInto(ctx, resp)
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (c *Assets) getPresetRequest(
+	p GetPresetXQuery,
+) *APIClient {
 
 	queryParams := make(map[string]string)
 
-	apiClient := &APIClient{
+	return &APIClient{
 		Conf:        c.config,
 		Method:      "get",
 		Url:         fmt.Sprintf("/service/platform/assets/v1.0/presets/%s", p.PresetName),
@@ -1090,21 +1722,15 @@ func (c *Assets) GetPreset(
 		ContentType: "",
 	}
 
-	response, err := apiClient.Execute()
-	if err != nil {
-		return nil, err
-	}
-	resp := map[string]interface{}{}
-	err = json.Unmarshal(response, &resp)
-	if err != nil {
-		return nil, common.NewFDKError(err.Error())
-	}
-	return resp, nil
-
 }
 
+// FileUploadXQuery holds the FileUpload parameters.
+// File may be any io.Reader. FileName defaults to the Name() of File when it has one, and FileSize,
+// when unset, is taken from File if it exposes Stat() or Len(); a known size lets the request carry a Content-Length.
 type FileUploadXQuery struct {
-	File             *os.File               `json:"file,omitempty"`
+	File             io.Reader              `json:"file,omitempty"`
+	FileName         string                 `json:"-"`
+	FileSize         int64                  `json:"-"`
 	Path             string                 `json:"path,omitempty"`
 	Name             string                 `json:"name,omitempty"`
 	Access           AccessEnum             `json:"access,omitempty"`
@@ -1124,10 +1750,47 @@ params: FileUploadXQuery
 func (c *Assets) FileUpload(
 	p FileUploadXQuery,
 ) (map[string]interface{}, error) {
+	return c.FileUploadWithContext(context.Background(), p)
+}
 
-	type body struct {
-		File *os.File `json:"file,omitempty"`
+// FileUploadWithContext is like FileUpload but binds the request to ctx.
+func (c *Assets) FileUploadWithContext(
+	ctx context.Context,
+	p FileUploadXQuery,
+) (map[string]interface{}, error) {
+	resp := map[string]interface{}{}
+	err := c.fileUploadRequest(p).executeInto(ctx, &resp)
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// FileUploadTyped is like FileUpload but decodes the response into UploadResponse.
+func (c *Assets) FileUploadTyped(
+	p FileUploadXQuery,
+) (*UploadResponse, error) {
+	return c.FileUploadTypedWithContext(context.Background(), p)
+}
+
+// FileUploadTypedWithContext is like FileUploadTyped but binds the request to ctx.
+func (c *Assets) FileUploadTypedWithContext(
+	ctx context.Context,
+	p FileUploadXQuery,
+) (*UploadResponse, error) {
+	resp := &UploadResponse{}
+	err := c.fileUploadRequest(p).executeInto(ctx, resp)
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (c *Assets) fileUploadRequest(
+	p FileUploadXQuery,
+) *APIClient {
 
+	type body struct {
 		Path string `json:"path,omitempty"`
 
 		Name string `json:"name,omitempty"`
@@ -1144,8 +1807,6 @@ func (c *Assets) FileUpload(
 	}
 	bodydata := &body{
 
-		File: p.File,
-
 		Path: p.Path,
 
 		Name: p.Name,
@@ -1163,26 +1824,22 @@ func (c *Assets) FileUpload(
 
 	queryParams := make(map[string]string)
 
-	apiClient := &APIClient{
-		Conf:        c.config,
-		Method:      "post",
-		Url:         "/service/platform/assets/v1.0/upload/direct",
-		Query:       queryParams,
-		Body:        bodydata,
+	return &APIClient{
+		Conf:   c.config,
+		Method: "post",
+		Url:    "/service/platform/assets/v1.0/upload/direct",
+		Query:  queryParams,
+		Body: &common.MultipartBody{
+			File: common.FormFile{
+				Reader: p.File,
+				Name:   p.FileName,
+				Size:   p.FileSize,
+			},
+			Fields: bodydata,
+		},
 		ContentType: "multipart/form-data",
 	}
 
-	response, err := apiClient.Execute()
-	if err != nil {
-		return nil, err
-	}
-	resp := map[string]interface{}{}
-	err = json.Unmarshal(response, &resp)
-	if err != nil {
-		return nil, common.NewFDKError(err.Error())
-	}
-	return resp, nil
-
 }
 
 type UrlUploadXQuery struct {
@@ -1206,6 +1863,45 @@ params: UrlUploadXQuery
 func (c *Assets) UrlUpload(
 	p UrlUploadXQuery,
 ) (map[string]interface{}, error) {
+	return c.UrlUploadWithContext(context.Background(), p)
+}
+
+// UrlUploadWithContext is like UrlUpload but binds the request to ctx.
+func (c *Assets) UrlUploadWithContext(
+	ctx context.Context,
+	p UrlUploadXQuery,
+) (map[string]interface{}, error) {
+	resp := map[string]interface{}{}
+	err := c.urlUploadRequest(p).executeInto(ctx, &resp)
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// UrlUploadTyped is like UrlUpload but decodes the response into UploadResponse.
+func (c *Assets) UrlUploadTyped(
+	p UrlUploadXQuery,
+) (*UploadResponse, error) {
+	return c.UrlUploadTypedWithContext(context.Background(), p)
+}
+
+// UrlUploadTypedWithContext is like UrlUploadTyped but binds the request to ctx.
+func (c *Assets) UrlUploadTypedWithContext(
+	ctx context.Context,
+	p UrlUploadXQuery,
+) (*UploadResponse, error) {
+	resp := &UploadResponse{}
+	err := c.urlUploadRequest(p).executeInto(ctx, resp)
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (c *Assets) urlUploadRequest(
+	p UrlUploadXQuery,
+) *APIClient {
 
 	type body struct {
 		URL string `json:"url,omitempty"`
@@ -1245,7 +1941,7 @@ func (c *Assets) UrlUpload(
 
 	queryParams := make(map[string]string)
 
-	apiClient := &APIClient{
+	return &APIClient{
 		Conf:        c.config,
 		Method:      "post",
 		Url:         "/service/platform/assets/v1.0/upload/url",
@@ -1254,17 +1950,6 @@ func (c *Assets) UrlUpload(
 		ContentType: "application/json",
 	}
 
-	response, err := apiClient.Execute()
-	if err != nil {
-		return nil, err
-	}
-	resp := map[string]interface{}{}
-	err = json.Unmarshal(response, &resp)
-	if err != nil {
-		return nil, common.NewFDKError(err.Error())
-	}
-	return resp, nil
-
 }
 
 type CreateSignedUrlXQuery struct {
@@ -1289,13 +1974,52 @@ params: CreateSignedUrlXQuery
 func (c *Assets) CreateSignedUrl(
 	p CreateSignedUrlXQuery,
 ) (map[string]interface{}, error) {
+	return c.CreateSignedUrlWithContext(context.Background(), p)
+}
 
-	type body struct {
-		Name string `json:"name,omitempty"`
-
-		Path string `json:"path,omitempty"`
+// CreateSignedUrlWithContext is like CreateSignedUrl but binds the request to ctx.
+func (c *Assets) CreateSignedUrlWithContext(
+	ctx context.Context,
+	p CreateSignedUrlXQuery,
+) (map[string]interface{}, error) {
+	resp := map[string]interface{}{}
+	err := c.createSignedUrlRequest(p).executeInto(ctx, &resp)
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
 
-		Format string `json:"format,omitempty"`
+// CreateSignedUrlTyped is like CreateSignedUrl but decodes the response into SignedUploadResponse.
+func (c *Assets) CreateSignedUrlTyped(
+	p CreateSignedUrlXQuery,
+) (*SignedUploadResponse, error) {
+	return c.CreateSignedUrlTypedWithContext(context.Background(), p)
+}
+
+// CreateSignedUrlTypedWithContext is like CreateSignedUrlTyped but binds the request to ctx.
+func (c *Assets) CreateSignedUrlTypedWithContext(
+	ctx context.Context,
+	p CreateSignedUrlXQuery,
+) (*SignedUploadResponse, error) {
+	resp := &SignedUploadResponse{}
+	err := c.createSignedUrlRequest(p).executeInto(ctx, resp)
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (c *Assets) createSignedUrlRequest(
+	p CreateSignedUrlXQuery,
+) *APIClient {
+
+	type body struct {
+		Name string `json:"name,omitempty"`
+
+		Path string `json:"path,omitempty"`
+
+		Format string `json:"format,omitempty"`
 
 		Access AccessEnum `json:"access,omitempty"`
 
@@ -1328,7 +2052,7 @@ func (c *Assets) CreateSignedUrl(
 
 	queryParams := make(map[string]string)
 
-	apiClient := &APIClient{
+	return &APIClient{
 		Conf:        c.config,
 		Method:      "post",
 		Url:         "/service/platform/assets/v1.0/upload/signed-url",
@@ -1337,17 +2061,6 @@ func (c *Assets) CreateSignedUrl(
 		ContentType: "application/json",
 	}
 
-	response, err := apiClient.Execute()
-	if err != nil {
-		return nil, err
-	}
-	resp := map[string]interface{}{}
-	err = json.Unmarshal(response, &resp)
-	if err != nil {
-		return nil, common.NewFDKError(err.Error())
-	}
-	return resp, nil
-
 }
 
 type CreateSignedUrlV2XQuery struct {
@@ -1372,6 +2085,45 @@ params: CreateSignedUrlV2XQuery
 func (c *Assets) CreateSignedUrlV2(
 	p CreateSignedUrlV2XQuery,
 ) (map[string]interface{}, error) {
+	return c.CreateSignedUrlV2WithContext(context.Background(), p)
+}
+
+// CreateSignedUrlV2WithContext is like CreateSignedUrlV2 but binds the request to ctx.
+func (c *Assets) CreateSignedUrlV2WithContext(
+	ctx context.Context,
+	p CreateSignedUrlV2XQuery,
+) (map[string]interface{}, error) {
+	resp := map[string]interface{}{}
+	err := c.createSignedUrlV2Request(p).executeInto(ctx, &resp)
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// CreateSignedUrlV2Typed is like CreateSignedUrlV2 but decodes the response into SignedUploadV2Response.
+func (c *Assets) CreateSignedUrlV2Typed(
+	p CreateSignedUrlV2XQuery,
+) (*SignedUploadV2Response, error) {
+	return c.CreateSignedUrlV2TypedWithContext(context.Background(), p)
+}
+
+// CreateSignedUrlV2TypedWithContext is like CreateSignedUrlV2Typed but binds the request to ctx.
+func (c *Assets) CreateSignedUrlV2TypedWithContext(
+	ctx context.Context,
+	p CreateSignedUrlV2XQuery,
+) (*SignedUploadV2Response, error) {
+	resp := &SignedUploadV2Response{}
+	err := c.createSignedUrlV2Request(p).executeInto(ctx, resp)
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (c *Assets) createSignedUrlV2Request(
+	p CreateSignedUrlV2XQuery,
+) *APIClient {
 
 	type body struct {
 		Name string `json:"name,omitempty"`
@@ -1415,7 +2167,7 @@ func (c *Assets) CreateSignedUrlV2(
 
 	queryParams := make(map[string]string)
 
-	apiClient := &APIClient{
+	return &APIClient{
 		Conf:        c.config,
 		Method:      "post",
 		Url:         "/service/platform/assets/v2.0/upload/signed-url",
@@ -1424,17 +2176,6 @@ func (c *Assets) CreateSignedUrlV2(
 		ContentType: "application/json",
 	}
 
-	response, err := apiClient.Execute()
-	if err != nil {
-		return nil, err
-	}
-	resp := map[string]interface{}{}
-	err = json.Unmarshal(response, &resp)
-	if err != nil {
-		return nil, common.NewFDKError(err.Error())
-	}
-	return resp, nil
-
 }
 
 // Organization holds Organization object properties
@@ -1460,10 +2201,49 @@ params: GetAppOrgDetailsXQuery
 func (c *Organization) GetAppOrgDetails(
 	p GetAppOrgDetailsXQuery,
 ) (map[string]interface{}, error) {
+	return c.GetAppOrgDetailsWithContext(context.Background(), p)
+}
+
+// GetAppOrgDetailsWithContext is like GetAppOrgDetails but binds the request to ctx.
+func (c *Organization) GetAppOrgDetailsWithContext(
+	ctx context.Context,
+	p GetAppOrgDetailsXQuery,
+) (map[string]interface{}, error) {
+	resp := map[string]interface{}{}
+	err := c.getAppOrgDetailsRequest(p).executeInto(ctx, &resp)
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// GetAppOrgDetailsTyped is like GetAppOrgDetails but decodes the response into AppOrgDetails.
+func (c *Organization) GetAppOrgDetailsTyped(
+	p GetAppOrgDetailsXQuery,
+) (*AppOrgDetails, error) {
+	return c.GetAppOrgDetailsTypedWithContext(context.Background(), p)
+}
+
+// GetAppOrgDetailsTypedWithContext is like GetAppOrgDetailsTyped but binds the request to ctx.
+func (c *Organization) GetAppOrgDetailsTypedWithContext(
+	ctx context.Context,
+	p GetAppOrgDetailsXQuery,
+) (*AppOrgDetails, error) {
+	resp := &AppOrgDetails{}
+	err := c.getAppOrgDetailsRequest(p).executeInto(ctx, resp)
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (c *Organization) getAppOrgDetailsRequest(
+	p GetAppOrgDetailsXQuery,
+) *APIClient {
 
 	queryParams := make(map[string]string)
 
-	apiClient := &APIClient{
+	return &APIClient{
 		Conf:        c.config,
 		Method:      "get",
 		Url:         "/service/platform/organization/v1.0/apps/info",
@@ -1472,17 +2252,6 @@ func (c *Organization) GetAppOrgDetails(
 		ContentType: "",
 	}
 
-	response, err := apiClient.Execute()
-	if err != nil {
-		return nil, err
-	}
-	resp := map[string]interface{}{}
-	err = json.Unmarshal(response, &resp)
-	if err != nil {
-		return nil, common.NewFDKError(err.Error())
-	}
-	return resp, nil
-
 }
 
 // Transformation holds Transformation object properties
@@ -1509,6 +2278,45 @@ params: GetTransformationContextXQuery
 func (c *Transformation) GetTransformationContext(
 	p GetTransformationContextXQuery,
 ) (map[string]interface{}, error) {
+	return c.GetTransformationContextWithContext(context.Background(), p)
+}
+
+// GetTransformationContextWithContext is like GetTransformationContext but binds the request to ctx.
+func (c *Transformation) GetTransformationContextWithContext(
+	ctx context.Context,
+	p GetTransformationContextXQuery,
+) (map[string]interface{}, error) {
+	resp := map[string]interface{}{}
+	err := c.getTransformationContextRequest(p).executeInto(ctx, &resp)
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// GetTransformationContextTyped is like GetTransformationContext but decodes the response into GetTransformationContextSuccessResponse.
+func (c *Transformation) GetTransformationContextTyped(
+	p GetTransformationContextXQuery,
+) (*GetTransformationContextSuccessResponse, error) {
+	return c.GetTransformationContextTypedWithContext(context.Background(), p)
+}
+
+// GetTransformationContextTypedWithContext is like GetTransformationContextTyped but binds the request to ctx.
+func (c *Transformation) GetTransformationContextTypedWithContext(
+	ctx context.Context,
+	p GetTransformationContextXQuery,
+) (*GetTransformationContextSuccessResponse, error) {
+	resp := &GetTransformationContextSuccessResponse{}
+	err := c.getTransformationContextRequest(p).executeInto(ctx, resp)
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (c *Transformation) getTransformationContextRequest(
+	p GetTransformationContextXQuery,
+) *APIClient {
 
 	queryParams := make(map[string]string)
 
@@ -1516,7 +2324,7 @@ func (c *Transformation) GetTransformationContext(
 		queryParams["url"] = fmt.Sprintf("%v", p.URL)
 	}
 
-	apiClient := &APIClient{
+	return &APIClient{
 		Conf:        c.config,
 		Method:      "get",
 		Url:         "/service/platform/transformation/context",
@@ -1525,17 +2333,6 @@ func (c *Transformation) GetTransformationContext(
 		ContentType: "",
 	}
 
-	response, err := apiClient.Execute()
-	if err != nil {
-		return nil, err
-	}
-	resp := map[string]interface{}{}
-	err = json.Unmarshal(response, &resp)
-	if err != nil {
-		return nil, common.NewFDKError(err.Error())
-	}
-	return resp, nil
-
 }
 
 type Uploader struct {
@@ -1559,16 +2356,22 @@ type UploaderUploadXQuery struct {
 	Expiry           float64                `json:"expiry,omitempty"`
 }
 
-type uploaderOption func(*uploaderUploadConfig) error
+// UploaderOption customizes an upload, see WithChunkSize, WithConcurrency, WithCheckpoint and WithProgress
+type UploaderOption func(*uploaderUploadConfig) error
 
 type uploaderUploadConfig struct {
 	ChunkSize         uint
 	MaxRetries        uint
 	Concurrency       uint
 	ExponentialFactor uint
+
+	retryPolicy     RetryPolicy
+	checkpointStore CheckpointStore
+	checkpointKey   string
+	progress        func(UploadProgress)
 }
 
-func WithChunkSize(size uint) uploaderOption {
+func WithChunkSize(size uint) UploaderOption {
 	return func(c *uploaderUploadConfig) error {
 		if size <= 0 {
 			return fmt.Errorf("chunk size must be greater than 0")
@@ -1578,14 +2381,15 @@ func WithChunkSize(size uint) uploaderOption {
 	}
 }
 
-func WithMaxRetries(retries uint) uploaderOption {
+func WithMaxRetries(retries uint) UploaderOption {
 	return func(c *uploaderUploadConfig) error {
 		c.MaxRetries = retries
+		c.retryPolicy.MaxAttempts = retries + 1
 		return nil
 	}
 }
 
-func WithConcurrency(concurrency uint) uploaderOption {
+func WithConcurrency(concurrency uint) UploaderOption {
 	return func(c *uploaderUploadConfig) error {
 		if concurrency == 0 {
 			return fmt.Errorf("concurrency must be greater than 0")
@@ -1595,43 +2399,108 @@ func WithConcurrency(concurrency uint) uploaderOption {
 	}
 }
 
-func WithExponentialFactor(factor uint) uploaderOption {
+func WithExponentialFactor(factor uint) UploaderOption {
 	return func(c *uploaderUploadConfig) error {
 		c.ExponentialFactor = factor
+		c.retryPolicy.Multiplier = float64(factor)
+		return nil
+	}
+}
+
+// WithCheckpoint saves the upload state under key after every finished part, so that an interrupted upload
+// can be completed with ResumeUpload. Checkpoints go to DefaultCheckpointStore unless WithCheckpointStore is given too.
+// The checkpoint is deleted once the upload completes.
+func WithCheckpoint(key string) UploaderOption {
+	return func(c *uploaderUploadConfig) error {
+		if key == "" {
+			return fmt.Errorf("checkpoint key must not be empty")
+		}
+		c.checkpointKey = key
+		if c.checkpointStore == nil {
+			c.checkpointStore = DefaultCheckpointStore()
+		}
 		return nil
 	}
 }
 
-func retryWithExponentialBackoff(baseDelay time.Duration, exponentialFactor float64) func(n uint, err error, config *retry.Config) time.Duration {
-	initialDelay := float64(baseDelay)
-	return func(n uint, err error, config *retry.Config) time.Duration {
-		delay := initialDelay * math.Pow(exponentialFactor, float64(n))
-		return time.Duration(delay)
+// WithCheckpointStore keeps the checkpoints of WithCheckpoint in store
+func WithCheckpointStore(store CheckpointStore) UploaderOption {
+	return func(c *uploaderUploadConfig) error {
+		if store == nil {
+			return fmt.Errorf("checkpoint store must not be nil")
+		}
+		c.checkpointStore = store
+		return nil
 	}
 }
 
-func (u *Uploader) Upload(file io.Reader, p UploaderUploadXQuery, opts ...uploaderOption) (map[string]interface{}, error) {
-	config := &uploaderUploadConfig{
-		ChunkSize:         10 * 1024 * 1024, // 10MB default
-		MaxRetries:        2,
-		Concurrency:       3,
-		ExponentialFactor: 2,
+func (u *Uploader) Upload(file io.Reader, p UploaderUploadXQuery, opts ...UploaderOption) (map[string]interface{}, error) {
+	return u.UploadWithContext(context.Background(), file, p, opts...)
+}
+
+// UploadWithContext is like Upload but binds every request of the upload to ctx.
+// Cancelling ctx stops in-flight chunk uploads and skips pending ones.
+func (u *Uploader) UploadWithContext(ctx context.Context, file io.Reader, p UploaderUploadXQuery, opts ...UploaderOption) (map[string]interface{}, error) {
+	config, err := u.uploadConfig(opts)
+	if err != nil {
+		return nil, err
 	}
 
-	for _, opt := range opts {
-		if err := opt(config); err != nil {
-			return nil, fmt.Errorf("invalid option: %w", err)
-		}
+	session, err := u.createUploadSession(ctx, p, config)
+	if err != nil {
+		return nil, err
 	}
+	return u.multipartUploadToPixelBin(ctx, session, file, config)
+}
 
-	if config.ChunkSize == 0 {
-		return nil, fmt.Errorf("chunk size must be greater than 0")
+// UploadFile uploads the local file at path. Unlike Upload, it reads and retries every part independently,
+// so parts are read from disk in parallel, and all of them are exactly ChunkSize long except the last one.
+func (u *Uploader) UploadFile(path string, p UploaderUploadXQuery, opts ...UploaderOption) (map[string]interface{}, error) {
+	return u.UploadFileWithContext(context.Background(), path, p, opts...)
+}
+
+// UploadFileWithContext is like UploadFile but binds every request of the upload to ctx
+func (u *Uploader) UploadFileWithContext(ctx context.Context, path string, p UploaderUploadXQuery, opts ...UploaderOption) (map[string]interface{}, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
 	}
-	if config.Concurrency == 0 {
-		return nil, fmt.Errorf("concurrency must be greater than 0")
+	defer file.Close()
+	info, err := file.Stat()
+	if err != nil {
+		return nil, err
+	}
+	if !info.Mode().IsRegular() {
+		return nil, fmt.Errorf("%s is not a regular file", path)
+	}
+	return u.UploadReaderAtWithContext(ctx, file, info.Size(), p, opts...)
+}
+
+// UploadReaderAt uploads the first size bytes of r. Part boundaries are computed from size, parts are read
+// in parallel with io.SectionReader and streamed to the server, and a retried part is read again from r.
+func (u *Uploader) UploadReaderAt(r io.ReaderAt, size int64, p UploaderUploadXQuery, opts ...UploaderOption) (map[string]interface{}, error) {
+	return u.UploadReaderAtWithContext(context.Background(), r, size, p, opts...)
+}
+
+// UploadReaderAtWithContext is like UploadReaderAt but binds every request of the upload to ctx
+func (u *Uploader) UploadReaderAtWithContext(ctx context.Context, r io.ReaderAt, size int64, p UploaderUploadXQuery, opts ...UploaderOption) (map[string]interface{}, error) {
+	if size < 0 {
+		return nil, fmt.Errorf("size must not be negative")
+	}
+	config, err := u.uploadConfig(opts)
+	if err != nil {
+		return nil, err
+	}
+	session, err := u.createUploadSession(ctx, p, config)
+	if err != nil {
+		return nil, err
 	}
+	return u.multipartUploadFromReaderAt(ctx, session, r, size, config)
+}
 
-	signedUrlV2ApiResponse, err := u.assets.CreateSignedUrlV2(CreateSignedUrlV2XQuery{
+// createUploadSession gets a presigned URL for the upload and saves its first checkpoint
+func (u *Uploader) createUploadSession(ctx context.Context, p UploaderUploadXQuery, config *uploaderUploadConfig) (*uploadSession, error) {
+	signedUrlV2ApiResponse, err := u.assets.CreateSignedUrlV2WithContext(ctx, CreateSignedUrlV2XQuery{
 		Name:             p.Name,
 		Path:             p.Path,
 		Format:           p.Format,
@@ -1640,6 +2509,7 @@ func (u *Uploader) Upload(file io.Reader, p UploaderUploadXQuery, opts ...upload
 		Metadata:         p.Metadata,
 		Overwrite:        p.Overwrite,
 		FilenameOverride: p.FilenameOverride,
+		Expiry:           p.Expiry,
 	})
 	if err != nil {
 		return nil, fmt.Errorf("error creating signed URL: %w", err)
@@ -1660,130 +2530,384 @@ func (u *Uploader) Upload(file io.Reader, p UploaderUploadXQuery, opts ...upload
 		return nil, fmt.Errorf("fields not found in presignedUrl")
 	}
 
-	return u.multipartUploadToPixelBin(uploadURL, fields, file, config)
+	session := newUploadSession(uploadURL, fields, config)
+	if err = session.save(); err != nil {
+		return nil, fmt.Errorf("error saving upload checkpoint: %w", err)
+	}
+	return session, nil
+}
+
+// ResumeUpload completes the upload checkpointed under key by Upload with the WithCheckpoint option.
+// file must hold the same bytes as the interrupted upload: parts already uploaded are skipped by seeking past them.
+// Options other than WithCheckpoint apply as for Upload, except for the chunk size, which is the one of the checkpoint.
+// Resuming fails once the presigned URL has expired, see UploaderUploadXQuery.Expiry.
+func (u *Uploader) ResumeUpload(file io.ReadSeeker, key string, opts ...UploaderOption) (map[string]interface{}, error) {
+	return u.ResumeUploadWithContext(context.Background(), file, key, opts...)
+}
+
+// ResumeUploadWithContext is like ResumeUpload but binds every request of the upload to ctx
+func (u *Uploader) ResumeUploadWithContext(ctx context.Context, file io.ReadSeeker, key string, opts ...UploaderOption) (map[string]interface{}, error) {
+	config, err := u.uploadConfig(append(opts, WithCheckpoint(key)))
+	if err != nil {
+		return nil, err
+	}
+	checkpoint, err := config.checkpointStore.Load(key)
+	if err != nil {
+		return nil, fmt.Errorf("error loading upload checkpoint: %w", err)
+	}
+	if checkpoint == nil {
+		return nil, fmt.Errorf("no upload checkpoint found for %q", key)
+	}
+	if checkpoint.ChunkSize == 0 {
+		return nil, fmt.Errorf("upload checkpoint %q has no chunk size", key)
+	}
+	config.ChunkSize = checkpoint.ChunkSize
+	return u.multipartUploadToPixelBin(ctx, resumeUploadSession(checkpoint, config), file, config)
+}
+
+// uploadConfig applies opts over the default upload settings and the retry policy of the client config
+func (u *Uploader) uploadConfig(opts []UploaderOption) (*uploaderUploadConfig, error) {
+	config := &uploaderUploadConfig{
+		ChunkSize:         10 * 1024 * 1024, // 10MB default
+		MaxRetries:        2,
+		Concurrency:       3,
+		ExponentialFactor: 2,
+		retryPolicy: RetryPolicy{
+			MaxAttempts: 3,
+			BaseDelay:   time.Second,
+			MaxDelay:    time.Second * 60,
+			Multiplier:  2,
+		},
+	}
+	// the fields set on the policy of the config drive uploads too, explicit uploader options still take precedence
+	if policy := u.config.RetryPolicy; policy != nil {
+		if policy.MaxAttempts > 0 {
+			config.retryPolicy.MaxAttempts = policy.MaxAttempts
+			config.MaxRetries = policy.MaxAttempts - 1
+		}
+		if policy.BaseDelay > 0 {
+			config.retryPolicy.BaseDelay = policy.BaseDelay
+		}
+		if policy.MaxDelay > 0 {
+			config.retryPolicy.MaxDelay = policy.MaxDelay
+		}
+		if policy.Multiplier > 0 {
+			config.retryPolicy.Multiplier = policy.Multiplier
+		}
+		if policy.Jitter > 0 {
+			config.retryPolicy.Jitter = policy.Jitter
+		}
+	}
+
+	for _, opt := range opts {
+		if err := opt(config); err != nil {
+			return nil, fmt.Errorf("invalid option: %w", err)
+		}
+	}
+
+	if config.ChunkSize == 0 {
+		return nil, fmt.Errorf("chunk size must be greater than 0")
+	}
+	if config.Concurrency == 0 {
+		return nil, fmt.Errorf("concurrency must be greater than 0")
+	}
+	return config, nil
 }
 
-func (u *Uploader) multipartUploadToPixelBin(uploadURL string, fields map[string]interface{}, file io.Reader, config *uploaderUploadConfig) (map[string]interface{}, error) {
+// multipartUploadToPixelBin uploads file in parts of config.ChunkSize to the session, then completes it.
+// The first part that fails for good cancels the others and stops reading; the session is then left to expire,
+// or kept for ResumeUpload when it is checkpointed.
+func (u *Uploader) multipartUploadToPixelBin(parent context.Context, session *uploadSession, file io.Reader, config *uploaderUploadConfig) (map[string]interface{}, error) {
+	ctx, cancel := context.WithCancel(parent)
+	defer cancel()
 	var wg sync.WaitGroup
-	errors := make(chan error, config.Concurrency)
+	failures := &partFailures{cancel: cancel}
 	semaphore := make(chan struct{}, config.Concurrency)
+	progress := newUploadProgress(config.progress, readerSize(file), config.ChunkSize)
+	buffers := chunkPool(config.ChunkSize)
+
+	fail := func(err error) (map[string]interface{}, error) {
+		cancel()
+		wg.Wait()
+		return nil, abandonUpload(parent, failures, err)
+	}
 
 	partNumber := 0
 	for {
+		if err := ctx.Err(); err != nil {
+			return fail(err)
+		}
+
+		partNumber++
+		if session.isCompleted(partNumber) {
+			skipped, last, err := skipChunk(file, int64(config.ChunkSize))
+			if err != nil {
+				return fail(err)
+			}
+			progress.skipped(skipped)
+			if last {
+				break
+			}
+			continue
+		}
 
-		chunk := make([]byte, config.ChunkSize)
-		n, err := file.Read(chunk)
+		// reading waits for a free upload slot, so at most Concurrency chunks are held in memory
+		select {
+		case semaphore <- struct{}{}:
+		case <-ctx.Done():
+			return fail(ctx.Err())
+		}
+		buffer := buffers.Get().(*[]byte)
+		release := func() {
+			buffers.Put(buffer)
+			<-semaphore
+		}
+
+		// parts must be exactly ChunkSize long, except the last one, for a resumed upload to split the file the same way
+		n, err := io.ReadFull(file, *buffer)
 		if err == io.EOF {
+			release()
+			partNumber--
 			break
 		}
-		if err != nil {
-			return nil, err
+		if err != nil && err != io.ErrUnexpectedEOF {
+			release()
+			return fail(fmt.Errorf("error reading part %d: %w", partNumber, err))
 		}
 
-		partNumber++
 		wg.Add(1)
-		go func(pn int, data []byte) {
+		go func(pn int, part *io.SectionReader) {
 			defer wg.Done()
-			semaphore <- struct{}{}
-			defer func() { <-semaphore }()
+			defer release()
+			u.uploadPart(ctx, session, config, progress, failures, pn, part)
+		}(partNumber, io.NewSectionReader(bytes.NewReader((*buffer)[:n]), 0, int64(n)))
 
-			err := uploadChunk(uploadURL, fields, data[:n], pn, config.MaxRetries, config.ExponentialFactor)
-			if err != nil {
-				select {
-				case errors <- err:
-				default:
-				}
-			}
-		}(partNumber, chunk)
+		if err == io.ErrUnexpectedEOF {
+			break
+		}
 	}
 
 	wg.Wait()
-	close(errors)
+	return u.completeUpload(ctx, parent, session, config, failures, partNumber)
+}
 
-	if err := <-errors; err != nil {
-		return nil, err
+// multipartUploadFromReaderAt is like multipartUploadToPixelBin for the size bytes of r. Parts are streamed
+// from r without being buffered, so nothing limits memory but Concurrency.
+func (u *Uploader) multipartUploadFromReaderAt(parent context.Context, session *uploadSession, r io.ReaderAt, size int64, config *uploaderUploadConfig) (map[string]interface{}, error) {
+	ctx, cancel := context.WithCancel(parent)
+	defer cancel()
+	var wg sync.WaitGroup
+	failures := &partFailures{cancel: cancel}
+	semaphore := make(chan struct{}, config.Concurrency)
+	progress := newUploadProgress(config.progress, size, config.ChunkSize)
+
+	chunkSize := int64(config.ChunkSize)
+	numParts := int((size + chunkSize - 1) / chunkSize)
+	for partNumber := 1; partNumber <= numParts; partNumber++ {
+		offset := int64(partNumber-1) * chunkSize
+		length := chunkSize
+		if offset+length > size {
+			length = size - offset
+		}
+		if session.isCompleted(partNumber) {
+			progress.skipped(length)
+			continue
+		}
+
+		select {
+		case semaphore <- struct{}{}:
+		case <-ctx.Done():
+			wg.Wait()
+			return nil, abandonUpload(parent, failures, ctx.Err())
+		}
+		wg.Add(1)
+		go func(pn int, part *io.SectionReader) {
+			defer wg.Done()
+			defer func() { <-semaphore }()
+			u.uploadPart(ctx, session, config, progress, failures, pn, part)
+		}(partNumber, io.NewSectionReader(r, offset, length))
+	}
+
+	wg.Wait()
+	return u.completeUpload(ctx, parent, session, config, failures, numParts)
+}
+
+// uploadPart uploads one part and records it in the session checkpoint and progress, or records its failure
+func (u *Uploader) uploadPart(ctx context.Context, session *uploadSession, config *uploaderUploadConfig, progress *uploadProgress, failures *partFailures, partNumber int, part *io.SectionReader) {
+	err := uploadChunk(ctx, u.config.GetHTTPClient(), session.uploadURL, session.fields, part, partNumber, &config.retryPolicy, progress)
+	if err == nil {
+		if err = session.partDone(partNumber); err != nil {
+			err = fmt.Errorf("error saving upload checkpoint: %w", err)
+		}
 	}
+	if err != nil {
+		failures.add(partNumber, err)
+		return
+	}
+	progress.completed(partNumber, part.Size())
+}
 
-	return completeMultipartUpload(uploadURL, fields, partNumber, config.MaxRetries, config.ExponentialFactor)
+// completeUpload completes the session once its numParts parts are uploaded, or gives it up when some failed
+func (u *Uploader) completeUpload(ctx, parent context.Context, session *uploadSession, config *uploaderUploadConfig, failures *partFailures, numParts int) (map[string]interface{}, error) {
+	if err := failures.err(); err != nil {
+		return nil, abandonUpload(parent, failures, err)
+	}
+	result, err := completeMultipartUpload(ctx, u.config.GetHTTPClient(), session.uploadURL, session.fields, numParts, &config.retryPolicy)
+	if err != nil {
+		return nil, abandonUpload(parent, failures, err)
+	}
+	if err = session.finish(); err != nil {
+		return nil, fmt.Errorf("error deleting upload checkpoint: %w", err)
+	}
+	return result, nil
 }
 
-func uploadChunk(uploadURL string, fields map[string]interface{}, chunk []byte, partNumber int, maxRetries uint, exponentialFactor uint) error {
-	return retry.Do(
-		func() error {
-			body := &bytes.Buffer{}
-			writer := multipart.NewWriter(body)
-
-			for key, value := range fields {
-				err := writer.WriteField(key, fmt.Sprintf("%v", value))
-				if err != nil {
-					return err
-				}
-			}
+// abandonUpload returns the error to report for a failed session: the cancellation of parent, else the failed parts, else err.
+// The session itself is left to expire on the server, or to be resumed when it is checkpointed.
+func abandonUpload(parent context.Context, failures *partFailures, err error) error {
+	if parent.Err() != nil {
+		return parent.Err()
+	}
+	if partsErr := failures.err(); partsErr != nil {
+		return partsErr
+	}
+	return err
+}
 
-			part, err := writer.CreateFormFile("file", "file")
-			if err != nil {
-				return err
-			}
-			_, err = part.Write(chunk)
-			if err != nil {
-				return err
-			}
+// chunkPools holds a *sync.Pool of chunk buffers per chunk size, shared by all uploads.
+// A buffer goes back to its pool only once the transport closed every request body reading it.
+var chunkPools sync.Map
 
-			err = writer.Close()
-			if err != nil {
-				return err
-			}
+// chunkPool returns the pool of *[]byte buffers of the given size
+func chunkPool(size uint) *sync.Pool {
+	if pool, ok := chunkPools.Load(size); ok {
+		return pool.(*sync.Pool)
+	}
+	pool, _ := chunkPools.LoadOrStore(size, &sync.Pool{
+		New: func() interface{} {
+			buffer := make([]byte, size)
+			return &buffer
+		},
+	})
+	return pool.(*sync.Pool)
+}
 
-			urlObj, err := url.Parse(uploadURL)
-			if err != nil {
-				return err
+// skipChunk seeks file past a part uploaded before the upload was resumed and returns its size.
+// last reports that the part was the final one of the file.
+func skipChunk(file io.Reader, size int64) (skipped int64, last bool, err error) {
+	seeker, ok := file.(io.Seeker)
+	if !ok {
+		return 0, false, fmt.Errorf("resuming an upload needs a seekable file")
+	}
+	offset, err := seeker.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return 0, false, err
+	}
+	end, err := seeker.Seek(0, io.SeekEnd)
+	if err != nil {
+		return 0, false, err
+	}
+	if offset+size >= end {
+		return end - offset, true, nil
+	}
+	_, err = seeker.Seek(offset+size, io.SeekStart)
+	return size, false, err
+}
+
+func uploadChunk(ctx context.Context, client *http.Client, uploadURL string, fields map[string]interface{}, part *io.SectionReader, partNumber int, policy *RetryPolicy, progress *uploadProgress) error {
+	// the form fields and file part headers are written once; the part itself is streamed
+	// between them, and read again from the start on every attempt
+	envelope := &bytes.Buffer{}
+	writer := multipart.NewWriter(envelope)
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if err := writer.WriteField(key, fmt.Sprintf("%v", fields[key])); err != nil {
+			return err
+		}
+	}
+	if _, err := writer.CreateFormFile("file", "file"); err != nil {
+		return err
+	}
+	head := envelope.Len()
+	if err := writer.Close(); err != nil {
+		return err
+	}
+
+	urlObj, err := url.Parse(uploadURL)
+	if err != nil {
+		return err
+	}
+	q := urlObj.Query()
+	q.Set("partNumber", strconv.Itoa(partNumber))
+	urlObj.RawQuery = q.Encode()
+
+	attempt := 0
+	return retry.Do(
+		func() error {
+			if attempt++; attempt > 1 {
+				progress.retried(partNumber)
 			}
-			q := urlObj.Query()
-			q.Set("partNumber", strconv.Itoa(partNumber))
-			urlObj.RawQuery = q.Encode()
 
-			req, err := http.NewRequest("PUT", urlObj.String(), body)
+			partBody := progress.reader(partNumber, io.NewSectionReader(part, 0, part.Size()))
+			defer partBody.finish()
+			body := newPartBody(io.MultiReader(
+				bytes.NewReader(envelope.Bytes()[:head]),
+				partBody,
+				bytes.NewReader(envelope.Bytes()[head:]),
+			))
+			req, err := http.NewRequestWithContext(ctx, "PUT", urlObj.String(), body)
 			if err != nil {
 				return err
 			}
-
+			// the transport may go on reading the body after Do returns, and the part buffer is reused
+			// once this returns, so wait for the transport to close the body
+			defer body.wait()
+			req.ContentLength = int64(envelope.Len()) + part.Size()
 			req.Header.Set("Content-Type", writer.FormDataContentType())
 
-			client := &http.Client{}
 			resp, err := client.Do(req)
 			if err != nil {
 				return err
 			}
 			defer resp.Body.Close()
 
-			if resp.StatusCode >= 400 && resp.StatusCode < 500 {
-				var errResp common.FDKError
-				err = json.NewDecoder(resp.Body).Decode(&errResp)
-				if err != nil {
-					return retry.Unrecoverable(err)
-				}
-				return retry.Unrecoverable(&errResp)
-			}
-
-			if resp.StatusCode >= 500 {
-				var errResp common.FDKError
-				err = json.NewDecoder(resp.Body).Decode(&errResp)
-				if err != nil {
-					return err
-				}
-				return &errResp
+			if resp.StatusCode >= 400 {
+				return multipartResponseError(resp)
 			}
 
 			return nil
 		},
-		retry.Attempts(maxRetries+1),
-		retry.DelayType(retryWithExponentialBackoff(time.Second, float64(exponentialFactor))),
-		retry.MaxDelay(time.Second*60),
-		retry.LastErrorOnly(true),
+		policy.options(ctx)...,
 	)
 }
 
-func completeMultipartUpload(uploadURL string, fields map[string]interface{}, numParts int, maxRetries uint, exponentialFactor uint) (map[string]interface{}, error) {
+// partBody is the request body of one attempt at uploading a part, telling when the transport closed it
+type partBody struct {
+	io.Reader
+	once   sync.Once
+	closed chan struct{}
+}
+
+func newPartBody(r io.Reader) *partBody {
+	return &partBody{Reader: r, closed: make(chan struct{})}
+}
+
+// Close is called by the transport once it is done with the body, which the RoundTripper contract requires
+func (b *partBody) Close() error {
+	b.once.Do(func() { close(b.closed) })
+	return nil
+}
+
+// wait returns once the body is closed
+func (b *partBody) wait() {
+	<-b.closed
+}
+
+func completeMultipartUpload(ctx context.Context, client *http.Client, uploadURL string, fields map[string]interface{}, numParts int, policy *RetryPolicy) (map[string]interface{}, error) {
 	var result map[string]interface{}
 
 	err := retry.Do(
@@ -1810,41 +2934,33 @@ func completeMultipartUpload(uploadURL string, fields map[string]interface{}, nu
 				return err
 			}
 
-			resp, err := http.Post(urlObj.String(), "application/json", bytes.NewBuffer(jsonPayload))
+			req, err := http.NewRequestWithContext(ctx, "POST", urlObj.String(), bytes.NewBuffer(jsonPayload))
 			if err != nil {
 				return err
 			}
-			defer resp.Body.Close()
+			req.Header.Set("Content-Type", "application/json")
 
-			if resp.StatusCode >= 400 && resp.StatusCode < 500 {
-				var errResp common.FDKError
-				err = json.NewDecoder(resp.Body).Decode(&errResp)
-				if err != nil {
-					return retry.Unrecoverable(err)
-				}
-				return retry.Unrecoverable(&errResp)
+			resp, err := client.Do(req)
+			if err != nil {
+				return err
 			}
+			defer resp.Body.Close()
 
-			if resp.StatusCode >= 500 {
-				var errResp common.FDKError
-				err = json.NewDecoder(resp.Body).Decode(&errResp)
-				if err != nil {
-					return err
-				}
-				return &errResp
+			if resp.StatusCode >= 400 {
+				return multipartResponseError(resp)
 			}
 
-			err = json.NewDecoder(resp.Body).Decode(&result)
+			data, err := io.ReadAll(resp.Body)
 			if err != nil {
 				return err
 			}
-
-			return nil
+			result = map[string]interface{}{}
+			if len(bytes.TrimSpace(data)) == 0 {
+				return nil
+			}
+			return json.Unmarshal(data, &result)
 		},
-		retry.Attempts(maxRetries+1),
-		retry.DelayType(retryWithExponentialBackoff(time.Second, float64(exponentialFactor))),
-		retry.MaxDelay(time.Second*60),
-		retry.LastErrorOnly(true),
+		policy.options(ctx)...,
 	)
 
 	if err != nil {
@@ -1853,3 +2969,12 @@ func completeMultipartUpload(uploadURL string, fields map[string]interface{}, nu
 
 	return result, nil
 }
+
+// multipartResponseError turns a failed chunk or complete response into an FDKError carrying its status and headers
+func multipartResponseError(resp *http.Response) error {
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	return common.NewFDKErrorFromResponse(resp.StatusCode, resp.Header, data)
+}
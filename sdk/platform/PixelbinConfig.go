@@ -1,15 +1,32 @@
 package platform
 
+import (
+	"net/http"
+
+	"github.com/pixelbin-io/pixelbin-go/v3/sdk/common"
+)
+
 // PixelbinConfig provides configuration to a service client instance.
 type PixelbinConfig struct {
 	ApiSecret   string
 	Domain      string
 	OAuthClient *OAuthClient
+	// HTTPClient is used for platform API calls and uploader requests.
+	// When nil, common.DefaultHTTPClient is used.
+	HTTPClient *http.Client
+	// RetryPolicy is applied to platform API calls and uploads. When nil, API calls are not retried.
+	RetryPolicy *RetryPolicy
+	// Middlewares wrap every platform API call, the first one being the outermost
+	Middlewares []Middleware
 }
 
 // NewPixelbinConfig provides pixelbin configuration
 func NewPixelbinConfig(apiSecret, domain string) *PixelbinConfig {
-	return &PixelbinConfig{apiSecret, domain, &OAuthClient{}}
+	return &PixelbinConfig{
+		ApiSecret:   apiSecret,
+		Domain:      domain,
+		OAuthClient: &OAuthClient{},
+	}
 }
 
 // SetOAuthClient sets OAuthClient into pixelbin configuration
@@ -18,6 +35,34 @@ func (p *PixelbinConfig) SetOAuthClient() {
 
 }
 
+// SetHTTPClient sets the http.Client used for every request made with this configuration
+func (p *PixelbinConfig) SetHTTPClient(client *http.Client) {
+	p.HTTPClient = client
+}
+
+// SetTransport sets the http.RoundTripper used for every request made with this configuration
+func (p *PixelbinConfig) SetTransport(transport http.RoundTripper) {
+	p.HTTPClient = &http.Client{Transport: transport}
+}
+
+// SetRetryPolicy sets the retry policy used for platform API calls and uploads
+func (p *PixelbinConfig) SetRetryPolicy(policy *RetryPolicy) {
+	p.RetryPolicy = policy
+}
+
+// Use appends middlewares that wrap every platform API call made with this configuration
+func (p *PixelbinConfig) Use(middlewares ...Middleware) {
+	p.Middlewares = append(p.Middlewares, middlewares...)
+}
+
+// GetHTTPClient returns the configured http.Client, or the shared default client
+func (p *PixelbinConfig) GetHTTPClient() *http.Client {
+	if p.HTTPClient != nil {
+		return p.HTTPClient
+	}
+	return common.DefaultHTTPClient
+}
+
 // GetAccessToken returns the access token
 func (p *PixelbinConfig) GetAccessToken() string {
 	return p.OAuthClient.GetAccessToken()
@@ -1,9 +1,14 @@
 package platform
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
 	"fmt"
+	"net/url"
 	"strings"
 
+	"github.com/avast/retry-go/v4"
 	"github.com/pixelbin-io/pixelbin-go/v3/sdk/common"
 )
 
@@ -15,10 +20,17 @@ type APIClient struct {
 	Query       map[string]string
 	Body        interface{}
 	ContentType string
+	// QueryValues holds the query parameters that may repeat, e.g. tags=a&tags=b, sent along with Query
+	QueryValues url.Values
 }
 
 // Execute performs API call
 func (c *APIClient) Execute() ([]byte, error) {
+	return c.ExecuteWithContext(context.Background())
+}
+
+// ExecuteWithContext performs API call bound to ctx
+func (c *APIClient) ExecuteWithContext(ctx context.Context) ([]byte, error) {
 	var token string = common.EncodeToBase64(c.Conf.GetAccessToken())
 	headers := map[string]string{
 		"Authorization": fmt.Sprintf("Bearer %s", token),
@@ -40,5 +52,58 @@ func (c *APIClient) Execute() ([]byte, error) {
 
 	host := strings.Replace(strings.Replace(c.Conf.Domain, "http://", "", 1), "https://", "", 1)
 	headers["host"] = host
-	return common.HttpRequest(strings.ToUpper(c.Method), fmt.Sprintf("%s%s", c.Conf.Domain, c.Url), c.Query, c.Body, headers)
+	if key := idempotencyKeyFromContext(ctx); key != "" {
+		headers[IdempotencyKeyHeader] = key
+	}
+
+	method := strings.ToUpper(c.Method)
+	handler := chainMiddlewares(c.Conf.Middlewares, c.do)
+	send := func() ([]byte, error) {
+		// every attempt starts from the original query and headers, whatever earlier attempts' middleware did
+		req := &Request{Method: method, Path: c.Url, Query: make(map[string]string, len(c.Query)), QueryValues: cloneValues(c.QueryValues), Body: c.Body, Header: make(map[string]string, len(headers))}
+		for k, v := range c.Query {
+			req.Query[k] = v
+		}
+		for k, v := range headers {
+			req.Header[k] = v
+		}
+		res, err := handler(ctx, req)
+		if err != nil || res == nil {
+			return []byte{}, err
+		}
+		return res.Body, nil
+	}
+
+	policy := c.Conf.RetryPolicy
+	// multipart bodies stream the caller's file, which cannot be replayed once consumed
+	if !policy.allows(ctx, method) || c.ContentType == "multipart/form-data" {
+		return send()
+	}
+	return retry.DoWithData(send, policy.options(ctx)...)
+}
+
+// executeInto performs API call bound to ctx and decodes the JSON response into out.
+// An empty body, e.g. from a 204 No Content, leaves out untouched.
+func (c *APIClient) executeInto(ctx context.Context, out interface{}) error {
+	response, err := c.ExecuteWithContext(ctx)
+	if err != nil {
+		return err
+	}
+	if len(bytes.TrimSpace(response)) == 0 {
+		return nil
+	}
+	// as for the untyped methods, a malformed body is an *FDKError, which unwraps to the decoding error
+	if err = json.Unmarshal(response, out); err != nil {
+		return common.NewFDKError(err.Error()).SetCause(err)
+	}
+	return nil
+}
+
+// do is the innermost Handler, sending the request over HTTP
+func (c *APIClient) do(ctx context.Context, req *Request) (*Response, error) {
+	res, err := common.DoHttpRequest(ctx, c.Conf.GetHTTPClient(), req.Method, fmt.Sprintf("%s%s", c.Conf.Domain, req.Path), req.values(), req.Body, req.Header)
+	if res == nil {
+		return nil, err
+	}
+	return &Response{StatusCode: res.StatusCode, Header: res.Header, Body: res.Body}, err
 }
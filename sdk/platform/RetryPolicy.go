@@ -0,0 +1,160 @@
+package platform
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/avast/retry-go/v4"
+	"github.com/pixelbin-io/pixelbin-go/v3/sdk/common"
+)
+
+// RetryPolicy controls how failed requests are retried.
+// It is applied to platform API calls when set on PixelbinConfig, and drives the Uploader chunk requests.
+//
+// Transient transport errors (connection resets, timeouts), 429 and 5xx responses are retried.
+// Other transport errors, such as TLS verification failures or unsupported URL schemes, are not.
+// Only idempotent methods are retried unless the request carries an idempotency key, see WithIdempotencyKey.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts including the first one. Values below 2 disable retries.
+	MaxAttempts uint
+	// BaseDelay is the delay before the first retry.
+	BaseDelay time.Duration
+	// MaxDelay caps every delay, including one requested by the server through Retry-After.
+	MaxDelay time.Duration
+	// Multiplier is the exponential growth factor applied to the delay after each attempt.
+	Multiplier float64
+	// Jitter randomises each delay by up to ±Jitter of its value, e.g. 0.2 for ±20%.
+	Jitter float64
+}
+
+// DefaultRetryPolicy returns a policy making up to 3 attempts with exponential backoff starting at 500ms and 20% jitter
+func DefaultRetryPolicy() *RetryPolicy {
+	return &RetryPolicy{
+		MaxAttempts: 3,
+		BaseDelay:   500 * time.Millisecond,
+		MaxDelay:    30 * time.Second,
+		Multiplier:  2,
+		Jitter:      0.2,
+	}
+}
+
+// IdempotencyKeyHeader is the header carrying the key set with WithIdempotencyKey
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+type idempotencyKeyContextKey struct{}
+
+// WithIdempotencyKey returns a copy of ctx whose platform API calls send key in the Idempotency-Key header.
+// Such calls may be retried by the RetryPolicy even when their method is not idempotent.
+func WithIdempotencyKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, idempotencyKeyContextKey{}, key)
+}
+
+func idempotencyKeyFromContext(ctx context.Context) string {
+	key, _ := ctx.Value(idempotencyKeyContextKey{}).(string)
+	return key
+}
+
+func isIdempotentMethod(method string) bool {
+	switch strings.ToUpper(method) {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// allows reports whether a request with the given method may be retried at all
+func (r *RetryPolicy) allows(ctx context.Context, method string) bool {
+	if r == nil || r.MaxAttempts < 2 {
+		return false
+	}
+	return isIdempotentMethod(method) || idempotencyKeyFromContext(ctx) != ""
+}
+
+// isRetryable reports whether err is a transient failure worth another attempt
+func (r *RetryPolicy) isRetryable(ctx context.Context, err error) bool {
+	if err == nil || ctx.Err() != nil {
+		return false
+	}
+	var fdkErr *common.FDKError
+	if errors.As(err, &fdkErr) {
+		return fdkErr.Status == http.StatusTooManyRequests ||
+			(fdkErr.Status >= 500 && fdkErr.Status != http.StatusNotImplemented)
+	}
+	if errors.Is(err, syscall.ECONNRESET) {
+		return true
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+	var temporary interface{ Temporary() bool }
+	return errors.As(err, &temporary) && temporary.Temporary()
+}
+
+// delay returns how long to wait before attempt n+1, preferring the server's Retry-After when present
+func (r *RetryPolicy) delay(n uint, err error) time.Duration {
+	if d, ok := retryAfter(err); ok {
+		return r.capDelay(d)
+	}
+	d := float64(r.BaseDelay)
+	if r.Multiplier > 0 {
+		d *= math.Pow(r.Multiplier, float64(n))
+	}
+	if r.Jitter > 0 {
+		d += d * r.Jitter * (2*rand.Float64() - 1)
+	}
+	return r.capDelay(time.Duration(d))
+}
+
+func (r *RetryPolicy) capDelay(d time.Duration) time.Duration {
+	if d < 0 {
+		return 0
+	}
+	if r.MaxDelay > 0 && d > r.MaxDelay {
+		return r.MaxDelay
+	}
+	return d
+}
+
+// options translates the policy into retry-go options bound to ctx
+func (r *RetryPolicy) options(ctx context.Context) []retry.Option {
+	attempts := r.MaxAttempts
+	if attempts == 0 {
+		// retry-go treats 0 attempts as "retry forever"
+		attempts = 1
+	}
+	return []retry.Option{
+		retry.Attempts(attempts),
+		retry.RetryIf(func(err error) bool { return r.isRetryable(ctx, err) }),
+		retry.DelayType(func(n uint, err error, _ *retry.Config) time.Duration { return r.delay(n, err) }),
+		retry.LastErrorOnly(true),
+		retry.Context(ctx),
+	}
+}
+
+// retryAfter extracts the Retry-After header, in seconds or HTTP-date form, from an FDKError
+func retryAfter(err error) (time.Duration, bool) {
+	var fdkErr *common.FDKError
+	if !errors.As(err, &fdkErr) || fdkErr.Header == nil {
+		return 0, false
+	}
+	value := strings.TrimSpace(fdkErr.Header.Get("Retry-After"))
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		return time.Until(at), true
+	}
+	return 0, false
+}
@@ -0,0 +1,425 @@
+package platform
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/pixelbin-io/pixelbin-go/v3/sdk/common"
+)
+
+// SyncChecksumMetadataKey is the metadata key under which Sync stores the sha256 of every file it uploads
+const SyncChecksumMetadataKey = "sha256"
+
+// ErrSyncCollision is reported for local files that would be uploaded to the same asset, e.g. logo.jpg and logo.jpeg
+var ErrSyncCollision = errors.New("pixelbin: local files map to the same asset")
+
+// SyncOptions controls PixelbinClient.Sync
+type SyncOptions struct {
+	// Checksum compares the sha256 of local files with the one Sync stored in the remote file metadata,
+	// catching changes that keep the size. It costs one GetFileByFileId call per file of matching size.
+	Checksum bool
+	// Delete removes remote files that have no local counterpart
+	Delete bool
+	// DryRun reports the changes without uploading or deleting anything
+	DryRun bool
+	// Access is the access level of uploaded files
+	Access AccessEnum
+	// Tags are set on uploaded files
+	Tags []string
+	// Concurrency is the number of files uploaded at the same time, 4 by default
+	Concurrency uint
+	// UploadOptions are passed to Uploader.Upload for every file
+	UploadOptions []UploaderOption
+	// DeleteOptions sets the batch size, concurrency and rate limit of the deletions; its DryRun is ignored
+	DeleteOptions BulkOptions
+}
+
+// SyncAction is what Sync did, or would do in dry-run mode, for one file
+type SyncAction string
+
+const (
+	// SyncUpload uploads a file missing from the remote folder
+	SyncUpload SyncAction = "upload"
+	// SyncUpdate uploads a file whose remote copy differs
+	SyncUpdate SyncAction = "update"
+	// SyncDelete deletes a remote file missing locally
+	SyncDelete SyncAction = "delete"
+	// SyncSkip leaves an unchanged file alone
+	SyncSkip SyncAction = "skip"
+)
+
+// SyncChange describes the outcome of Sync for one file
+type SyncChange struct {
+	Action SyncAction
+	// Path is the slash-separated file path relative to both localDir and remotePath, e.g. "img/logo.png"
+	Path string
+	// Size is the local size for uploads and skips, the remote size for deletes
+	Size int64
+	// FileId identifies the remote file, empty for new uploads
+	FileId string
+	// Err is set when the change failed
+	Err error
+}
+
+// SyncReport lists every file considered by Sync, sorted by path
+type SyncReport struct {
+	DryRun  bool
+	Changes []SyncChange
+}
+
+// Count returns the number of changes with the given action, failed ones included
+func (r *SyncReport) Count(action SyncAction) int {
+	n := 0
+	for _, change := range r.Changes {
+		if change.Action == action {
+			n++
+		}
+	}
+	return n
+}
+
+// Failed returns the changes that could not be applied
+func (r *SyncReport) Failed() []SyncChange {
+	var failed []SyncChange
+	for _, change := range r.Changes {
+		if change.Err != nil {
+			failed = append(failed, change)
+		}
+	}
+	return failed
+}
+
+// String renders the report one change per line, e.g. "upload img/logo.png (2048 bytes)"
+func (r *SyncReport) String() string {
+	var b strings.Builder
+	for _, change := range r.Changes {
+		fmt.Fprintf(&b, "%s %s (%d bytes)", change.Action, change.Path, change.Size)
+		if change.Err != nil {
+			fmt.Fprintf(&b, ": %v", change.Err)
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// Sync makes the Pixelbin folder remotePath mirror the local directory localDir.
+//
+// Files are matched by path, name and format, then compared by size and, with SyncOptions.Checksum, by content hash.
+// New and changed files are uploaded with Uploader.Upload, overwriting the remote copy.
+// Local files that only differ by an alias of their extension, e.g. logo.jpg and logo.jpeg, are neither uploaded
+// nor deleted remotely: they fail with ErrSyncCollision.
+// Asset names should be URL safe, since Pixelbin slugifies names and a renamed asset no longer matches its local file.
+func (c *PixelbinClient) Sync(localDir, remotePath string, opts SyncOptions) (*SyncReport, error) {
+	return c.SyncWithContext(context.Background(), localDir, remotePath, opts)
+}
+
+// SyncWithContext is like Sync but binds every request to ctx.
+//
+// The returned error is set when the directories could not be compared, or when at least one change failed;
+// the report then tells which ones.
+func (c *PixelbinClient) SyncWithContext(ctx context.Context, localDir, remotePath string, opts SyncOptions) (*SyncReport, error) {
+	if opts.Concurrency == 0 {
+		opts.Concurrency = 4
+	}
+	remotePath = strings.Trim(remotePath, "/")
+
+	local, err := listLocalFiles(localDir)
+	if err != nil {
+		return nil, err
+	}
+	remote, err := c.listRemoteFiles(ctx, remotePath)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &SyncReport{DryRun: opts.DryRun}
+	var toUpload []syncUpload
+	for key, files := range local {
+		item, ok := remote[key]
+		delete(remote, key)
+		if len(files) > 1 {
+			report.Changes = append(report.Changes, collidingChanges(files, item.FileId)...)
+			continue
+		}
+		file := files[0]
+		change := SyncChange{Action: SyncUpload, Path: file.path, Size: file.size}
+		var checksum string
+		if ok {
+			change.FileId = item.FileId
+			change.Action, checksum, change.Err = c.compare(ctx, file, item, opts.Checksum)
+		}
+		if change.Action != SyncSkip && change.Err == nil {
+			toUpload = append(toUpload, syncUpload{index: len(report.Changes), checksum: checksum})
+		}
+		report.Changes = append(report.Changes, change)
+	}
+	var toDelete []int
+	var deleteIDs []string
+	if opts.Delete {
+		for _, item := range remote {
+			toDelete = append(toDelete, len(report.Changes))
+			deleteIDs = append(deleteIDs, item.ID)
+			report.Changes = append(report.Changes, SyncChange{
+				Action: SyncDelete,
+				Path:   item.path,
+				Size:   int64(item.Size),
+				FileId: item.FileId,
+			})
+		}
+	}
+
+	if !opts.DryRun {
+		c.syncUploads(ctx, localDir, remotePath, report, toUpload, opts)
+		c.syncDeletes(ctx, report, toDelete, deleteIDs, opts.DeleteOptions.withDefaults())
+	}
+	sort.Slice(report.Changes, func(i, j int) bool { return report.Changes[i].Path < report.Changes[j].Path })
+
+	if failed := report.Failed(); len(failed) > 0 {
+		return report, fmt.Errorf("sync: %d of %d changes failed, first on %s: %w", len(failed), len(report.Changes), failed[0].Path, failed[0].Err)
+	}
+	return report, ctx.Err()
+}
+
+// syncFile is a local file candidate for upload
+type syncFile struct {
+	// path is relative to the synced directory and slash-separated, local is the path on disk
+	path  string
+	local string
+	size  int64
+}
+
+// syncUpload is a pending upload: the index of its change in the report, and the checksum of the file
+// when comparing it already computed one
+type syncUpload struct {
+	index    int
+	checksum string
+}
+
+// remoteSyncFile is a remote file with its path relative to the synced folder
+type remoteSyncFile struct {
+	ExploreItem
+	path string
+}
+
+// syncKey identifies a file on both sides. Formats are normalised so that e.g. logo.jpg matches the asset logo in jpeg format.
+func syncKey(relPath string) string {
+	ext := path.Ext(relPath)
+	format := strings.ToLower(strings.TrimPrefix(ext, "."))
+	if alias, ok := formatAliases[format]; ok {
+		format = alias
+	}
+	return strings.TrimSuffix(relPath, ext) + "." + format
+}
+
+var formatAliases = map[string]string{
+	"jpg": "jpeg",
+	"tif": "tiff",
+}
+
+// collidingChanges reports files sharing a sync key as failed, each naming the others
+func collidingChanges(files []syncFile, fileId string) []SyncChange {
+	changes := make([]SyncChange, len(files))
+	for i, file := range files {
+		var others []string
+		for _, other := range files {
+			if other.path != file.path {
+				others = append(others, other.path)
+			}
+		}
+		action := SyncUpdate
+		if fileId == "" {
+			action = SyncUpload
+		}
+		changes[i] = SyncChange{
+			Action: action,
+			Path:   file.path,
+			Size:   file.size,
+			FileId: fileId,
+			Err:    fmt.Errorf("%w: %s and %s", ErrSyncCollision, file.path, strings.Join(others, ", ")),
+		}
+	}
+	return changes
+}
+
+// listLocalFiles returns the regular files below dir by sync key. Several files share a key when their
+// extensions are aliases of each other.
+func listLocalFiles(dir string) (map[string][]syncFile, error) {
+	files := make(map[string][]syncFile)
+	err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		key := syncKey(rel)
+		files[key] = append(files[key], syncFile{path: rel, local: p, size: info.Size()})
+		return nil
+	})
+	return files, err
+}
+
+// listRemoteFiles returns the files below remotePath by sync key. A missing remotePath has no files,
+// but a subfolder that cannot be listed, even because it is gone, fails the listing: it would be incomplete.
+func (c *PixelbinClient) listRemoteFiles(ctx context.Context, remotePath string) (map[string]remoteSyncFile, error) {
+	files := make(map[string]remoteSyncFile)
+	subfolderFailed := false
+	err := c.Assets.WalkWithContext(ctx, remotePath, func(entry WalkEntry, err error) error {
+		if err != nil {
+			subfolderFailed = true
+			return err
+		}
+		if entry.IsDir() {
+			return nil
+		}
+		rel := path.Join(strings.TrimPrefix(path.Dir(entry.Path), remotePath), assetFileName(entry.Item))
+		rel = strings.TrimPrefix(rel, "/")
+		files[syncKey(rel)] = remoteSyncFile{ExploreItem: entry.Item, path: rel}
+		return nil
+	})
+	if common.IsNotFound(err) && !subfolderFailed {
+		return files, nil
+	}
+	return files, err
+}
+
+// compare decides whether the remote copy of file is up to date. It returns the checksum of file
+// when it had to compute it, so that the upload does not hash the file again.
+func (c *PixelbinClient) compare(ctx context.Context, file syncFile, item remoteSyncFile, checksum bool) (SyncAction, string, error) {
+	if int64(item.Size) != file.size {
+		return SyncUpdate, "", nil
+	}
+	if !checksum {
+		return SyncSkip, "", nil
+	}
+	remote, err := c.Assets.GetFileByFileIdTypedWithContext(ctx, GetFileByFileIdXQuery{FileId: item.FileId})
+	if err != nil {
+		return SyncUpdate, "", err
+	}
+	sum, err := fileChecksum(file.local)
+	if err != nil {
+		return SyncUpdate, "", err
+	}
+	if stored, _ := remote.Metadata[SyncChecksumMetadataKey].(string); stored == sum {
+		return SyncSkip, sum, nil
+	}
+	return SyncUpdate, sum, nil
+}
+
+// syncUploads uploads the files of the given pending uploads, recording failures in the report
+func (c *PixelbinClient) syncUploads(ctx context.Context, localDir, remotePath string, report *SyncReport, uploads []syncUpload, opts SyncOptions) {
+	var wg sync.WaitGroup
+	semaphore := make(chan struct{}, opts.Concurrency)
+	for _, upload := range uploads {
+		change := &report.Changes[upload.index]
+		checksum := upload.checksum
+		select {
+		case semaphore <- struct{}{}:
+		case <-ctx.Done():
+			change.Err = ctx.Err()
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-semaphore }()
+			change.Err = c.uploadSyncFile(ctx, filepath.Join(localDir, filepath.FromSlash(change.Path)), remotePath, change.Path, checksum, opts)
+		}()
+	}
+	wg.Wait()
+}
+
+// uploadSyncFile uploads the file at localPath with its checksum in the metadata, computing it when sum is empty
+func (c *PixelbinClient) uploadSyncFile(ctx context.Context, localPath, remotePath, relPath, sum string, opts SyncOptions) error {
+	if sum == "" {
+		var err error
+		if sum, err = fileChecksum(localPath); err != nil {
+			return err
+		}
+	}
+	file, err := os.Open(localPath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	folder := path.Dir(relPath)
+	if folder == "." {
+		folder = ""
+	}
+	ext := path.Ext(relPath)
+	_, err = c.Uploader.UploadWithContext(ctx, file, UploaderUploadXQuery{
+		Name:      strings.TrimSuffix(path.Base(relPath), ext),
+		Path:      strings.Trim(remotePath+"/"+folder, "/"),
+		Format:    strings.TrimPrefix(ext, "."),
+		Access:    opts.Access,
+		Tags:      opts.Tags,
+		Metadata:  map[string]interface{}{SyncChecksumMetadataKey: sum},
+		Overwrite: true,
+	}, opts.UploadOptions...)
+	return err
+}
+
+// syncDeletes deletes the remote files ids, whose changes are at the given indexes of report.Changes,
+// in batches of opts.BatchSize. Files missing from the response are reported with ErrNotDeleted.
+func (c *PixelbinClient) syncDeletes(ctx context.Context, report *SyncReport, indexes []int, ids []string, opts BulkOptions) {
+	started := forEachBatch(ctx, len(ids), opts, func(from, to int) {
+		deleted, err := c.Assets.DeleteFilesTypedWithContext(ctx, DeleteFilesXQuery{Ids: ids[from:to]})
+		if err != nil {
+			for _, i := range indexes[from:to] {
+				report.Changes[i].Err = err
+			}
+			return
+		}
+		if deleted == nil {
+			// a success without body, nothing tells that some files were kept
+			return
+		}
+		done := make(map[string]bool, len(deleted))
+		for _, file := range deleted {
+			done[file.ID] = true
+		}
+		for j := from; j < to; j++ {
+			if !done[ids[j]] {
+				report.Changes[indexes[j]].Err = ErrNotDeleted
+			}
+		}
+	})
+	for _, i := range indexes[started:] {
+		report.Changes[i].Err = ctx.Err()
+	}
+}
+
+// fileChecksum returns the hex sha256 of the file at p
+func fileChecksum(p string) (string, error) {
+	file, err := os.Open(p)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+	hash := sha256.New()
+	if _, err = io.Copy(hash, file); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
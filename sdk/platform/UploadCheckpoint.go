@@ -0,0 +1,196 @@
+package platform
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// UploadCheckpoint is the persisted state of a multipart upload, enough to resume it with Uploader.ResumeUpload
+type UploadCheckpoint struct {
+	// UploadURL and Fields come from the CreateSignedUrlV2 response
+	UploadURL string                 `json:"uploadUrl"`
+	Fields    map[string]interface{} `json:"fields"`
+	// ChunkSize is the part size the upload started with; a resumed upload must split the file the same way
+	ChunkSize uint `json:"chunkSize"`
+	// CompletedParts lists the part numbers already uploaded, in increasing order
+	CompletedParts []int     `json:"completedParts"`
+	CreatedAt      time.Time `json:"createdAt"`
+}
+
+// CheckpointStore persists upload checkpoints under caller chosen keys
+type CheckpointStore interface {
+	// Load returns the checkpoint saved under key, or nil and no error when there is none
+	Load(key string) (*UploadCheckpoint, error)
+	// Save stores checkpoint under key, replacing any previous one
+	Save(key string, checkpoint *UploadCheckpoint) error
+	// Delete removes the checkpoint saved under key, if any
+	Delete(key string) error
+}
+
+// FileCheckpointStore is a CheckpointStore keeping one JSON file per checkpoint in Dir
+type FileCheckpointStore struct {
+	Dir string
+}
+
+// NewFileCheckpointStore returns a store keeping checkpoints in dir
+func NewFileCheckpointStore(dir string) *FileCheckpointStore {
+	return &FileCheckpointStore{Dir: dir}
+}
+
+// DefaultCheckpointStore returns the store used by WithCheckpoint when no other store is given,
+// keeping checkpoints in a pixelbin/uploads folder of the user cache directory (or of the temporary directory)
+func DefaultCheckpointStore() *FileCheckpointStore {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		dir = os.TempDir()
+	}
+	return NewFileCheckpointStore(filepath.Join(dir, "pixelbin", "uploads"))
+}
+
+func (s *FileCheckpointStore) path(key string) string {
+	return filepath.Join(s.Dir, url.PathEscape(key)+".json")
+}
+
+// Load implements CheckpointStore
+func (s *FileCheckpointStore) Load(key string) (*UploadCheckpoint, error) {
+	data, err := os.ReadFile(s.path(key))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	checkpoint := &UploadCheckpoint{}
+	if err = json.Unmarshal(data, checkpoint); err != nil {
+		return nil, fmt.Errorf("corrupt upload checkpoint %s: %w", s.path(key), err)
+	}
+	return checkpoint, nil
+}
+
+// Save implements CheckpointStore. The file is replaced atomically, so a crash never leaves a partial checkpoint.
+func (s *FileCheckpointStore) Save(key string, checkpoint *UploadCheckpoint) error {
+	data, err := json.Marshal(checkpoint)
+	if err != nil {
+		return err
+	}
+	if err = os.MkdirAll(s.Dir, 0o700); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(s.Dir, ".checkpoint-*")
+	if err != nil {
+		return err
+	}
+	_, err = tmp.Write(data)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), s.path(key))
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+	}
+	return err
+}
+
+// Delete implements CheckpointStore
+func (s *FileCheckpointStore) Delete(key string) error {
+	err := os.Remove(s.path(key))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+// uploadSession is a multipart upload in progress, optionally checkpointed after every part
+type uploadSession struct {
+	uploadURL string
+	fields    map[string]interface{}
+
+	store      CheckpointStore
+	key        string
+	mu         sync.Mutex
+	checkpoint *UploadCheckpoint
+	completed  map[int]bool
+}
+
+func newUploadSession(uploadURL string, fields map[string]interface{}, config *uploaderUploadConfig) *uploadSession {
+	return &uploadSession{
+		uploadURL: uploadURL,
+		fields:    fields,
+		store:     config.checkpointStore,
+		key:       config.checkpointKey,
+		completed: map[int]bool{},
+		checkpoint: &UploadCheckpoint{
+			UploadURL: uploadURL,
+			Fields:    fields,
+			ChunkSize: config.ChunkSize,
+			CreatedAt: time.Now(),
+		},
+	}
+}
+
+// resumeUploadSession restores a session from its checkpoint
+func resumeUploadSession(checkpoint *UploadCheckpoint, config *uploaderUploadConfig) *uploadSession {
+	session := &uploadSession{
+		uploadURL:  checkpoint.UploadURL,
+		fields:     checkpoint.Fields,
+		store:      config.checkpointStore,
+		key:        config.checkpointKey,
+		completed:  map[int]bool{},
+		checkpoint: checkpoint,
+	}
+	for _, part := range checkpoint.CompletedParts {
+		session.completed[part] = true
+	}
+	return session
+}
+
+func (s *uploadSession) checkpointed() bool {
+	return s.store != nil && s.key != ""
+}
+
+// isCompleted reports whether part was uploaded before the session was resumed
+func (s *uploadSession) isCompleted(part int) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.completed[part]
+}
+
+// save persists the current checkpoint
+func (s *uploadSession) save() error {
+	if !s.checkpointed() {
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.store.Save(s.key, s.checkpoint)
+}
+
+// partDone records part as uploaded and persists the checkpoint
+func (s *uploadSession) partDone(part int) error {
+	if !s.checkpointed() {
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.completed[part] = true
+	s.checkpoint.CompletedParts = append(s.checkpoint.CompletedParts, part)
+	sort.Ints(s.checkpoint.CompletedParts)
+	return s.store.Save(s.key, s.checkpoint)
+}
+
+// finish forgets the checkpoint of a completed upload
+func (s *uploadSession) finish() error {
+	if !s.checkpointed() {
+		return nil
+	}
+	return s.store.Delete(s.key)
+}
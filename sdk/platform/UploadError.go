@@ -0,0 +1,83 @@
+package platform
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// PartError is the failure of one part of a multipart upload
+type PartError struct {
+	PartNumber int
+	Err        error
+}
+
+func (e PartError) Error() string {
+	return fmt.Sprintf("part %d: %v", e.PartNumber, e.Err)
+}
+
+func (e PartError) Unwrap() error {
+	return e.Err
+}
+
+// UploadError is returned by the Uploader when parts of an upload failed. It lists every failed part,
+// sorted by part number; parts cancelled because of an earlier failure are left out.
+// The unfinished upload session is not aborted: its parts are discarded by the server once the session expires.
+type UploadError struct {
+	parts []PartError
+}
+
+func (e *UploadError) Error() string {
+	messages := make([]string, len(e.parts))
+	for i, part := range e.parts {
+		messages[i] = part.Error()
+	}
+	return fmt.Sprintf("upload failed on %d parts: %s", len(e.parts), strings.Join(messages, "; "))
+}
+
+// Parts returns every failed part with its cause, sorted by part number
+func (e *UploadError) Parts() []PartError {
+	return append([]PartError{}, e.parts...)
+}
+
+// Unwrap returns the error of the first failed part only, so errors.Is and errors.As do not see the others:
+// use Parts to inspect every failure
+func (e *UploadError) Unwrap() error {
+	if len(e.parts) == 0 {
+		return nil
+	}
+	return e.parts[0].Err
+}
+
+// partFailures collects the failures of the parts of an upload, cancelling the upload on the first one
+type partFailures struct {
+	mu     sync.Mutex
+	cancel context.CancelFunc
+	parts  []PartError
+}
+
+func (f *partFailures) add(partNumber int, err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if len(f.parts) > 0 && errors.Is(err, context.Canceled) {
+		// cancelled by the failure already recorded
+		return
+	}
+	f.parts = append(f.parts, PartError{PartNumber: partNumber, Err: err})
+	f.cancel()
+}
+
+// err returns an *UploadError listing the failed parts, or nil when none failed
+func (f *partFailures) err() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if len(f.parts) == 0 {
+		return nil
+	}
+	parts := append([]PartError{}, f.parts...)
+	sort.Slice(parts, func(i, j int) bool { return parts[i].PartNumber < parts[j].PartNumber })
+	return &UploadError{parts: parts}
+}
@@ -0,0 +1,185 @@
+package platform
+
+import (
+	"io"
+	"sync"
+	"time"
+)
+
+// UploadProgress describes how far an upload got. It is passed to the WithProgress callback
+// every time bytes of a part are sent, and every time a part completes or is retried.
+type UploadProgress struct {
+	// BytesSent counts the bytes of the parts sent so far, including those skipped by ResumeUpload.
+	// It grows as the request bodies are written; bytes sent again by a retried part are counted once.
+	BytesSent int64
+	// TotalBytes is the size of the file, -1 when the reader does not tell it
+	TotalBytes int64
+	// PartsCompleted counts the parts uploaded so far, including those skipped by ResumeUpload
+	PartsCompleted int
+	// TotalParts is the number of parts of the upload, 0 when the size of the file is unknown
+	TotalParts int
+	// Part is the part number this update is about, and PartRetries the number of times it was retried so far
+	Part        int
+	PartRetries int
+	// Retries counts the retries of all parts
+	Retries int
+	// Throughput is the average upload speed in bytes per second since the upload started, leaving out parts skipped by ResumeUpload
+	Throughput float64
+	// Elapsed is the time since the upload of parts started
+	Elapsed time.Duration
+}
+
+// WithProgress calls fn with the upload progress every time bytes of a part are sent, and every time a part
+// completes or is retried.
+// Calls are serialised, never concurrent, so fn should return quickly: it holds up the other parts meanwhile.
+func WithProgress(fn func(UploadProgress)) UploaderOption {
+	return func(c *uploaderUploadConfig) error {
+		c.progress = fn
+		return nil
+	}
+}
+
+// uploadProgress tracks the progress of an upload for the WithProgress callback. A nil tracker does nothing.
+type uploadProgress struct {
+	mu          sync.Mutex
+	fn          func(UploadProgress)
+	start       time.Time
+	state       UploadProgress
+	partRetries map[int]int
+	// partSent holds the most bytes sent by any attempt at each part
+	partSent map[int]int64
+	// skippedBytes are the bytes of parts skipped by ResumeUpload, left out of the throughput
+	skippedBytes int64
+}
+
+func newUploadProgress(fn func(UploadProgress), totalBytes int64, chunkSize uint) *uploadProgress {
+	if fn == nil {
+		return nil
+	}
+	p := &uploadProgress{fn: fn, start: time.Now(), partRetries: map[int]int{}, partSent: map[int]int64{}}
+	p.state.TotalBytes = totalBytes
+	if totalBytes >= 0 {
+		p.state.TotalParts = int((totalBytes + int64(chunkSize) - 1) / int64(chunkSize))
+	}
+	return p
+}
+
+// skipped records a part uploaded before the upload was resumed, without calling the callback
+func (p *uploadProgress) skipped(size int64) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.state.BytesSent += size
+	p.state.PartsCompleted++
+	p.skippedBytes += size
+}
+
+// retried records a new attempt at uploading part
+func (p *uploadProgress) retried(part int) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.partRetries[part]++
+	p.state.Retries++
+	p.report(part)
+}
+
+// completed records the upload of part, size bytes long
+func (p *uploadProgress) completed(part int, size int64) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.addSent(part, size)
+	p.state.PartsCompleted++
+	p.report(part)
+}
+
+// addSent raises the bytes sent for part to sent when an attempt got further than the earlier ones, p.mu held.
+// It reports whether BytesSent grew.
+func (p *uploadProgress) addSent(part int, sent int64) bool {
+	if sent <= p.partSent[part] {
+		return false
+	}
+	p.state.BytesSent += sent - p.partSent[part]
+	p.partSent[part] = sent
+	return true
+}
+
+// reader returns r counting the bytes of part as the request body of one attempt reads them.
+// finish must be called once the attempt is over, so that a late read by the transport reports nothing.
+func (p *uploadProgress) reader(part int, r io.Reader) *partReader {
+	return &partReader{r: r, progress: p, part: part}
+}
+
+// partReader is the body of one attempt at uploading a part, reporting the bytes read from it
+type partReader struct {
+	r        io.Reader
+	progress *uploadProgress
+	part     int
+	// read is only used by the reading goroutine, done is guarded by progress.mu
+	read int64
+	done bool
+}
+
+func (r *partReader) Read(b []byte) (int, error) {
+	n, err := r.r.Read(b)
+	if n > 0 && r.progress != nil {
+		r.read += int64(n)
+		p := r.progress
+		p.mu.Lock()
+		if !r.done && p.addSent(r.part, r.read) {
+			p.report(r.part)
+		}
+		p.mu.Unlock()
+	}
+	return n, err
+}
+
+// finish stops the reporting of r
+func (r *partReader) finish() {
+	if r.progress == nil {
+		return
+	}
+	r.progress.mu.Lock()
+	r.done = true
+	r.progress.mu.Unlock()
+}
+
+// report calls the callback, p.mu held
+func (p *uploadProgress) report(part int) {
+	p.state.Part = part
+	p.state.PartRetries = p.partRetries[part]
+	p.state.Elapsed = time.Since(p.start)
+	if seconds := p.state.Elapsed.Seconds(); seconds > 0 {
+		p.state.Throughput = float64(p.state.BytesSent-p.skippedBytes) / seconds
+	}
+	p.fn(p.state)
+}
+
+// readerSize returns the number of bytes left in r, or -1 when r does not tell it
+func readerSize(r io.Reader) int64 {
+	switch v := r.(type) {
+	case interface{ Len() int }:
+		return int64(v.Len())
+	case io.Seeker:
+		offset, err := v.Seek(0, io.SeekCurrent)
+		if err != nil {
+			return -1
+		}
+		end, err := v.Seek(0, io.SeekEnd)
+		if err != nil {
+			return -1
+		}
+		if _, err = v.Seek(offset, io.SeekStart); err != nil {
+			return -1
+		}
+		return end - offset
+	}
+	return -1
+}
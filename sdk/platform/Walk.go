@@ -0,0 +1,364 @@
+package platform
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"path"
+	"strings"
+	"sync"
+)
+
+// WalkEntry is a file or folder visited by Assets.Walk
+type WalkEntry struct {
+	// Path is the full path of the entry, e.g. "photos/cats/kitty" for the file kitty in folder photos/cats
+	Path string
+	// Depth is 1 for the direct children of the walked root, 2 for their children, and so on
+	Depth int
+	// Item is the entry as returned by ListFiles
+	Item ExploreItem
+}
+
+// IsDir reports whether the entry is a folder
+func (e WalkEntry) IsDir() bool {
+	return e.Item.Type == "folder"
+}
+
+// Name returns the last element of the entry path
+func (e WalkEntry) Name() string {
+	return e.Item.Name
+}
+
+// WalkFunc is called by Assets.Walk for every visited entry.
+//
+// err is non-nil when listing the folder entry failed; fn is then called a second time for that folder.
+// Returning fs.SkipDir from a folder skips its content, from a file it skips the remaining entries of its folder.
+// Any other error stops the walk and is returned by Walk.
+type WalkFunc func(entry WalkEntry, err error) error
+
+// WalkOrder is the order in which Assets.Walk visits folders
+type WalkOrder int
+
+const (
+	// DepthFirst visits the content of a folder right after the folder itself, like filepath.WalkDir
+	DepthFirst WalkOrder = iota
+	// BreadthFirst visits every entry of a depth before going one level deeper
+	BreadthFirst
+)
+
+type walkOption func(*walkConfig) error
+
+type walkConfig struct {
+	Order       WalkOrder
+	Concurrency uint
+}
+
+// WithWalkOrder sets the order in which folders are visited, DepthFirst by default
+func WithWalkOrder(order WalkOrder) walkOption {
+	return func(c *walkConfig) error {
+		if order != DepthFirst && order != BreadthFirst {
+			return fmt.Errorf("unknown walk order %d", order)
+		}
+		c.Order = order
+		return nil
+	}
+}
+
+// WithWalkConcurrency sets how many folders may be listed at the same time, 4 by default
+func WithWalkConcurrency(concurrency uint) walkOption {
+	return func(c *walkConfig) error {
+		if concurrency == 0 {
+			return fmt.Errorf("concurrency must be greater than 0")
+		}
+		c.Concurrency = concurrency
+		return nil
+	}
+}
+
+// Walk visits every file and folder below root, calling fn for each of them. The root itself is not reported.
+//
+// Up to WithWalkConcurrency folders are listed ahead of the walk, concurrently,
+// but fn is always called from a single goroutine, one entry at a time.
+// The listing of a folder skipped with fs.SkipDir is cancelled.
+func (c *Assets) Walk(root string, fn WalkFunc, opts ...walkOption) error {
+	return c.WalkWithContext(context.Background(), root, fn, opts...)
+}
+
+// WalkWithContext is like Walk but binds every listing request to ctx.
+// The walk stops with ctx's error once ctx is done.
+func (c *Assets) WalkWithContext(ctx context.Context, root string, fn WalkFunc, opts ...walkOption) error {
+	config := &walkConfig{
+		Order:       DepthFirst,
+		Concurrency: 4,
+	}
+	for _, opt := range opts {
+		if err := opt(config); err != nil {
+			return err
+		}
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	// stops listings prefetched for folders that will not be visited
+	defer cancel()
+
+	w := &walker{
+		ctx:      ctx,
+		assets:   c,
+		fn:       fn,
+		order:    config.Order,
+		ahead:    int(config.Concurrency),
+		sem:      make(chan struct{}, config.Concurrency),
+		listings: make(map[string]*folderListing),
+	}
+	root = strings.Trim(root, "/")
+	items, err := w.take(root)
+	if err != nil {
+		return err
+	}
+	if config.Order == BreadthFirst {
+		return w.walkBreadthFirst(root, items)
+	}
+	err = w.walkFolder(root, 1, items)
+	if err == fs.SkipDir {
+		return nil
+	}
+	return err
+}
+
+// folderListing is the pending or completed listing of one folder
+type folderListing struct {
+	done   chan struct{}
+	cancel context.CancelFunc
+	items  []ExploreItem
+	err    error
+}
+
+func (l *folderListing) wait(ctx context.Context) ([]ExploreItem, error) {
+	select {
+	case <-l.done:
+		return l.items, l.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+type walker struct {
+	ctx    context.Context
+	assets *Assets
+	fn     WalkFunc
+	order  WalkOrder
+	// ahead is the number of folders listed ahead of the walk
+	ahead int
+	sem   chan struct{}
+
+	mu sync.Mutex
+	// listings holds the listings started and not taken yet, at most ahead of them besides the one being taken
+	listings map[string]*folderListing
+	// queue holds the folders to list ahead, in the order the walk will reach them
+	queue []string
+}
+
+// start begins listing folderPath in the background, w.mu held
+func (w *walker) start(folderPath string) *folderListing {
+	ctx, cancel := context.WithCancel(w.ctx)
+	listing := &folderListing{done: make(chan struct{}), cancel: cancel}
+	w.listings[folderPath] = listing
+	go func() {
+		defer close(listing.done)
+		select {
+		case w.sem <- struct{}{}:
+			defer func() { <-w.sem }()
+		case <-ctx.Done():
+			listing.err = ctx.Err()
+			return
+		}
+		listing.items, listing.err = w.assets.ListFilesPaginatorWithContext(ctx, ListFilesXQuery{Path: folderPath}).Collect(0)
+	}()
+	return listing
+}
+
+// fill starts queued listings while fewer than w.ahead are held, w.mu held
+func (w *walker) fill() {
+	for len(w.listings) < w.ahead && len(w.queue) > 0 {
+		folderPath := w.queue[0]
+		w.queue = w.queue[1:]
+		if _, ok := w.listings[folderPath]; !ok {
+			w.start(folderPath)
+		}
+	}
+}
+
+// unqueue removes folderPath from the folders to list ahead, w.mu held
+func (w *walker) unqueue(folderPath string) {
+	for i, queued := range w.queue {
+		if queued == folderPath {
+			w.queue = append(w.queue[:i], w.queue[i+1:]...)
+			return
+		}
+	}
+}
+
+// take returns the listing of folderPath, starting it now if it was not listed ahead, and forgets it
+// so that memory is released as the walk goes
+func (w *walker) take(folderPath string) ([]ExploreItem, error) {
+	w.mu.Lock()
+	listing, ok := w.listings[folderPath]
+	if !ok {
+		w.unqueue(folderPath)
+		listing = w.start(folderPath)
+	}
+	w.mu.Unlock()
+
+	items, err := listing.wait(w.ctx)
+
+	w.mu.Lock()
+	delete(w.listings, folderPath)
+	listing.cancel()
+	w.fill()
+	w.mu.Unlock()
+	return items, err
+}
+
+// skip forgets folderPath, cancelling its listing if it was started, when fn skips the folder
+func (w *walker) skip(folderPath string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if listing, ok := w.listings[folderPath]; ok {
+		listing.cancel()
+		delete(w.listings, folderPath)
+	}
+	w.unqueue(folderPath)
+	w.fill()
+}
+
+// skipFolders skips every folder of items
+func (w *walker) skipFolders(parent string, items []ExploreItem) {
+	for _, item := range items {
+		if item.Type == "folder" {
+			w.skip(path.Join(parent, item.Name))
+		}
+	}
+}
+
+// prefetch queues every subfolder of items to be listed ahead. Depth-first, they are reached before
+// the folders queued earlier, breadth-first after them.
+func (w *walker) prefetch(parent string, items []ExploreItem) {
+	var folders []string
+	for _, item := range items {
+		if item.Type == "folder" {
+			folders = append(folders, path.Join(parent, item.Name))
+		}
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.order == DepthFirst {
+		w.queue = append(folders, w.queue...)
+	} else {
+		w.queue = append(w.queue, folders...)
+	}
+	w.fill()
+}
+
+// visit calls fn for entry, and again with the listing error if its content cannot be listed.
+// It returns the folder content to descend into, if any.
+func (w *walker) visit(entry WalkEntry) ([]ExploreItem, bool, error) {
+	if err := w.ctx.Err(); err != nil {
+		return nil, false, err
+	}
+	if err := w.fn(entry, nil); err != nil {
+		return nil, false, err
+	}
+	if !entry.IsDir() {
+		return nil, false, nil
+	}
+	items, err := w.take(entry.Path)
+	if err != nil {
+		if ctxErr := w.ctx.Err(); ctxErr != nil {
+			return nil, false, ctxErr
+		}
+		if err = w.fn(entry, err); err != nil {
+			return nil, false, err
+		}
+		return nil, false, nil
+	}
+	return items, true, nil
+}
+
+// walkFolder visits items, the content of folderPath, depth-first.
+// It returns fs.SkipDir when fn asked to skip the rest of folderPath.
+func (w *walker) walkFolder(folderPath string, depth int, items []ExploreItem) error {
+	w.prefetch(folderPath, items)
+	for i, item := range items {
+		entry := WalkEntry{Path: path.Join(folderPath, item.Name), Depth: depth, Item: item}
+		children, descend, err := w.visit(entry)
+		if err == fs.SkipDir {
+			if entry.IsDir() {
+				w.skip(entry.Path)
+				continue
+			}
+			w.skipFolders(folderPath, items[i+1:])
+			return fs.SkipDir
+		}
+		if err != nil {
+			return err
+		}
+		if descend {
+			err = w.walkFolder(entry.Path, depth+1, children)
+			if err != nil && err != fs.SkipDir {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// walkBreadthFirst visits every entry below root level by level
+func (w *walker) walkBreadthFirst(root string, rootItems []ExploreItem) error {
+	type folder struct {
+		path  string
+		depth int
+		items []ExploreItem
+	}
+	queue := []folder{{path: root, depth: 1, items: rootItems}}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		w.prefetch(current.path, current.items)
+		var subfolders []WalkEntry
+		for i, item := range current.items {
+			entry := WalkEntry{Path: path.Join(current.path, item.Name), Depth: current.depth, Item: item}
+			if err := w.ctx.Err(); err != nil {
+				return err
+			}
+			err := w.fn(entry, nil)
+			if err == fs.SkipDir {
+				if entry.IsDir() {
+					w.skip(entry.Path)
+					continue
+				}
+				w.skipFolders(current.path, current.items[i+1:])
+				break
+			}
+			if err != nil {
+				return err
+			}
+			if entry.IsDir() {
+				subfolders = append(subfolders, entry)
+			}
+		}
+		for _, entry := range subfolders {
+			items, err := w.take(entry.Path)
+			if err != nil {
+				if ctxErr := w.ctx.Err(); ctxErr != nil {
+					return ctxErr
+				}
+				if err = w.fn(entry, err); err != nil && err != fs.SkipDir {
+					return err
+				}
+				continue
+			}
+			queue = append(queue, folder{path: entry.Path, depth: current.depth + 1, items: items})
+		}
+	}
+	return nil
+}
@@ -26,6 +26,9 @@ type exploreItem struct {
 	S3Key    string     `json:"s3Key"`
 }
 
+// ExploreItem is the exported name of exploreItem, so that callers can refer to listed files and folders
+type ExploreItem = exploreItem
+
 // page used by Assets
 type page struct {
 	Type      string  `json:"type"`
@@ -0,0 +1,54 @@
+package tests
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pixelbin-io/pixelbin-go/v3/sdk/common"
+)
+
+func TestHostHeaderSetsRequestHost(t *testing.T) {
+	var host string
+	var hostHeaders []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host = r.Host
+		hostHeaders = r.Header.Values("Host")
+		w.Write([]byte(`{}`))
+	}))
+	defer srv.Close()
+
+	_, err := common.HttpRequestWithContext(context.Background(), "get", srv.URL, nil, nil, map[string]string{"host": "api.pixelbin.io"})
+	if err != nil {
+		t.Fatalf("Failed ! got err %v", err)
+	}
+	if host != "api.pixelbin.io" {
+		t.Errorf("Failed ! expected host api.pixelbin.io, got %q", host)
+	}
+	if len(hostHeaders) != 0 {
+		t.Errorf("Failed ! expected no extra Host header, got %v", hostHeaders)
+	}
+}
+
+func TestMultipartStreamClosedWhenRequestCannotBeBuilt(t *testing.T) {
+	before := runtime.NumGoroutine()
+	for i := 0; i < 20; i++ {
+		body := &common.MultipartBody{File: common.FormFile{Reader: strings.NewReader("data"), Name: "a.txt"}}
+		_, err := common.DoHttpRequest(context.Background(), nil, "POST", "http://[::1", nil, body, map[string]string{})
+		if err == nil {
+			t.Fatalf("Failed ! expected an invalid URL error")
+		}
+	}
+	// the stream writers exit asynchronously once their pipe is closed
+	deadline := time.Now().Add(time.Second)
+	for runtime.NumGoroutine() > before && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if leaked := runtime.NumGoroutine() - before; leaked > 0 {
+		t.Errorf("Failed ! %d multipart stream goroutines leaked", leaked)
+	}
+}
@@ -0,0 +1,181 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/pixelbin-io/pixelbin-go/v3/sdk/common"
+)
+
+// numberedPages serves pages of pageSize ints up to total items, counting fetches
+func numberedPages(total, pageSize int, fetches *int32) func(ctx context.Context, pageNo int) (*common.Page[int], error) {
+	return func(ctx context.Context, pageNo int) (*common.Page[int], error) {
+		atomic.AddInt32(fetches, 1)
+		var items []int
+		for i := (pageNo - 1) * pageSize; i < pageNo*pageSize && i < total; i++ {
+			items = append(items, i)
+		}
+		return &common.Page[int]{Items: items, HasNext: pageNo*pageSize < total}, nil
+	}
+}
+
+func TestNumberPaginatorCollect(t *testing.T) {
+	var fetches int32
+	all, err := common.NewNumberPaginator(context.Background(), 1, numberedPages(7, 3, &fetches)).Collect(0)
+	if err != nil {
+		t.Fatalf("Failed ! %v", err)
+	}
+	if fmt.Sprint(all) != "[0 1 2 3 4 5 6]" || fetches != 3 {
+		t.Errorf("Failed ! got %v in %d fetches", all, fetches)
+	}
+
+	fetches = 0
+	some, err := common.NewNumberPaginator(context.Background(), 1, numberedPages(7, 3, &fetches)).Collect(4)
+	if err != nil {
+		t.Fatalf("Failed ! %v", err)
+	}
+	if fmt.Sprint(some) != "[0 1 2 3]" || fetches != 2 {
+		t.Errorf("Failed ! Collect(4) got %v in %d fetches", some, fetches)
+	}
+}
+
+func TestPaginatorNextAfterCollect(t *testing.T) {
+	var fetches int32
+	paginator := common.NewNumberPaginator(context.Background(), 1, numberedPages(7, 3, &fetches), common.WithPrefetch(1))
+	defer paginator.Close()
+	if some, err := paginator.Collect(4); err != nil || len(some) != 4 {
+		t.Fatalf("Failed ! Collect(4) got %v and %v", some, err)
+	}
+	var rest []int
+	for paginator.Next() {
+		rest = append(rest, paginator.Item())
+	}
+	if fmt.Sprint(rest) != "[4 5 6]" || paginator.Err() != nil {
+		t.Errorf("Failed ! expected Next to carry on after Collect(4), got %v and %v", rest, paginator.Err())
+	}
+}
+
+func TestCursorPaginatorNextPage(t *testing.T) {
+	pages := map[string]*common.Page[string]{
+		"":   {Items: []string{"a", "b"}, HasNext: true, NextCursor: "c1"},
+		"c1": {Items: []string{"c"}, HasNext: true, NextCursor: "c2"},
+		"c2": {Items: []string{"d"}, HasNext: false},
+	}
+	paginator := common.NewCursorPaginator(context.Background(), "", func(ctx context.Context, cursor string) (*common.Page[string], error) {
+		return pages[cursor], nil
+	})
+	var got []string
+	for paginator.HasNext() {
+		items, err := paginator.NextPage()
+		if err != nil {
+			t.Fatalf("Failed ! %v", err)
+		}
+		got = append(got, fmt.Sprint(items))
+	}
+	if fmt.Sprint(got) != "[[a b] [c] [d]]" {
+		t.Errorf("Failed ! got pages %v", got)
+	}
+}
+
+func TestPaginatorForEachStopsOnError(t *testing.T) {
+	stop := errors.New("stop")
+	var fetches int32
+	seen := 0
+	stopped := common.NewNumberPaginator(context.Background(), 1, numberedPages(100, 10, &fetches))
+	err := stopped.ForEach(func(item int) error {
+		seen++
+		if item == 12 {
+			return stop
+		}
+		return nil
+	})
+	if !errors.Is(err, stop) || seen != 13 {
+		t.Errorf("Failed ! expected to stop after 13 items, got %d items and %v", seen, err)
+	}
+	if !stopped.Next() || stopped.Item() != 13 {
+		t.Errorf("Failed ! expected Next to carry on after ForEach stopped, got %v", stopped.Err())
+	}
+	stopped.Close()
+
+	boom := errors.New("boom")
+	paginator := common.NewNumberPaginator(context.Background(), 1, func(ctx context.Context, pageNo int) (*common.Page[int], error) {
+		if pageNo == 2 {
+			return nil, boom
+		}
+		return &common.Page[int]{Items: []int{pageNo}, HasNext: true}, nil
+	})
+	items, err := paginator.Collect(0)
+	if !errors.Is(err, boom) || len(items) != 1 {
+		t.Errorf("Failed ! expected the first page then boom, got %v and %v", items, err)
+	}
+}
+
+func TestPaginatorPrefetch(t *testing.T) {
+	var fetches int32
+	paginator := common.NewNumberPaginator(context.Background(), 1, numberedPages(50, 5, &fetches), common.WithPrefetch(2))
+	if !paginator.Next() || paginator.Item() != 0 {
+		t.Fatalf("Failed ! expected the first item, got %v", paginator.Err())
+	}
+	// pages are fetched ahead while the first one is being consumed
+	deadline := time.Now().Add(time.Second)
+	for atomic.LoadInt32(&fetches) < 3 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if n := atomic.LoadInt32(&fetches); n < 3 {
+		t.Errorf("Failed ! expected pages to be prefetched, got %d fetches", n)
+	}
+	paginator.Close()
+	if paginator.Next() || paginator.Err() != nil {
+		t.Errorf("Failed ! expected a closed paginator to stop without error, got %v", paginator.Err())
+	}
+	// the background fetch stops shortly after Close, well before all 10 pages
+	time.Sleep(20 * time.Millisecond)
+	if n := atomic.LoadInt32(&fetches); n >= 10 {
+		t.Errorf("Failed ! prefetch kept going after Close, got %d fetches", n)
+	}
+
+	var moreFetches int32
+	all, err := common.NewNumberPaginator(context.Background(), 1, numberedPages(50, 5, &moreFetches), common.WithPrefetch(3)).Collect(0)
+	if err != nil || len(all) != 50 || all[49] != 49 {
+		t.Errorf("Failed ! prefetching paginator returned %d items and %v", len(all), err)
+	}
+}
+
+func TestPaginatorStopsOnContextCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	var fetches int32
+	paginator := common.NewNumberPaginator(ctx, 1, numberedPages(50, 5, &fetches), common.WithPrefetch(1))
+	if !paginator.Next() {
+		t.Fatalf("Failed ! %v", paginator.Err())
+	}
+	cancel()
+	if paginator.Next() || !errors.Is(paginator.Err(), context.Canceled) {
+		t.Errorf("Failed ! expected context.Canceled, got %v", paginator.Err())
+	}
+}
+
+func TestPaginatorReleasesContextAfterLastPage(t *testing.T) {
+	for _, opts := range [][]common.PaginatorOption{nil, {common.WithPrefetch(2)}} {
+		var fetchCtx context.Context
+		var fetches int32
+		pages := numberedPages(5, 2, &fetches)
+		paginator := common.NewNumberPaginator(context.Background(), 1, func(ctx context.Context, pageNo int) (*common.Page[int], error) {
+			fetchCtx = ctx
+			return pages(ctx, pageNo)
+		}, opts...)
+		n := 0
+		for paginator.Next() {
+			n++
+		}
+		if n != 5 || paginator.Err() != nil {
+			t.Fatalf("Failed ! got %d items and err %v", n, paginator.Err())
+		}
+		if fetchCtx.Err() == nil {
+			t.Errorf("Failed ! expected the fetch context to be released once the last page is read, prefetch %v", opts != nil)
+		}
+	}
+}
@@ -0,0 +1,2011 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"mime"
+	"mime/multipart"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/pixelbin-io/pixelbin-go/v3/sdk/common"
+	"github.com/pixelbin-io/pixelbin-go/v3/sdk/platform"
+)
+
+func newLocalPixelbin(handler http.HandlerFunc) (*platform.PixelbinClient, *httptest.Server) {
+	srv := httptest.NewServer(handler)
+	return newPixelbinFor(srv, nil), srv
+}
+
+// newPixelbinFor returns a client of srv sending its requests through transport, the default one when nil
+func newPixelbinFor(srv *httptest.Server, transport http.RoundTripper) *platform.PixelbinClient {
+	conf := platform.NewPixelbinConfig("test-api-secret", srv.URL)
+	conf.SetOAuthClient()
+	if transport != nil {
+		conf.SetTransport(transport)
+	}
+	return platform.NewPixelbinClient(conf)
+}
+
+// writeListFilesPage answers a ListFiles call with items, a JSON array, as the one and only page
+func writeListFilesPage(w io.Writer, items string) {
+	fmt.Fprintf(w, `{"items":%s,"page":{"current":1,"hasNext":false}}`, items)
+}
+
+func TestListFilesWithContextDeadline(t *testing.T) {
+	client, srv := newLocalPixelbin(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(2 * time.Second):
+		}
+	})
+	defer srv.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err := client.Assets.ListFilesWithContext(ctx, platform.ListFilesXQuery{})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Failed ! expected deadline exceeded, got %v", err)
+	}
+	if time.Since(start) > time.Second {
+		t.Errorf("Failed ! request was not aborted by the context deadline")
+	}
+}
+
+func TestUploadWithContextCancelled(t *testing.T) {
+	client, srv := newLocalPixelbin(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("Failed ! unexpected request %s %s", r.Method, r.URL.Path)
+	})
+	defer srv.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := client.Uploader.UploadWithContext(ctx, nil, platform.UploaderUploadXQuery{Name: "cancelled"})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("Failed ! expected context cancelled, got %v", err)
+	}
+}
+
+type countingTransport struct {
+	mu    sync.Mutex
+	paths []string
+}
+
+func (c *countingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	c.mu.Lock()
+	c.paths = append(c.paths, req.Method+" "+req.URL.Path)
+	c.mu.Unlock()
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func TestCustomTransportUsedEverywhere(t *testing.T) {
+	srv := newFakeResumableServer(t)
+	defer srv.Close()
+
+	transport := &countingTransport{}
+	client := newPixelbinFor(srv.Server, transport)
+
+	_, err := client.Uploader.Upload(strings.NewReader("0123456789"), platform.UploaderUploadXQuery{Name: "myimage"},
+		platform.WithChunkSize(4),
+	)
+	if err != nil {
+		t.Fatalf("Failed ! got err %v", err)
+	}
+
+	expected := map[string]int{
+		"POST /service/platform/assets/v2.0/upload/signed-url": 1,
+		"PUT /upload":  3,
+		"POST /upload": 1,
+	}
+	got := map[string]int{}
+	for _, p := range transport.paths {
+		got[p]++
+	}
+	for k, v := range expected {
+		if got[k] != v {
+			t.Errorf("Failed ! expected %d x %q through the custom transport, got %d", v, k, got[k])
+		}
+	}
+}
+
+func TestRetryPolicyRetriesTransientFailures(t *testing.T) {
+	var calls int32
+	client, srv := newLocalPixelbin(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) < 3 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusServiceUnavailable)
+			fmt.Fprint(w, `{"message":"try again"}`)
+			return
+		}
+		fmt.Fprint(w, `{"items":[],"page":{"type":"number","current":1,"hasNext":false}}`)
+	})
+	defer srv.Close()
+	client.Config.SetRetryPolicy(&platform.RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond})
+
+	_, err := client.Assets.ListFiles(platform.ListFilesXQuery{})
+	if err != nil {
+		t.Fatalf("Failed ! got err %v", err)
+	}
+	if calls != 3 {
+		t.Errorf("Failed ! expected 3 attempts, got %d", calls)
+	}
+}
+
+func TestRetryPolicySkipsNonIdempotentWithoutKey(t *testing.T) {
+	var calls int32
+	var keys []string
+	client, srv := newLocalPixelbin(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		keys = append(keys, r.Header.Get(platform.IdempotencyKeyHeader))
+		w.WriteHeader(http.StatusBadGateway)
+		fmt.Fprint(w, `{"message":"bad gateway"}`)
+	})
+	defer srv.Close()
+	client.Config.SetRetryPolicy(&platform.RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond})
+
+	_, err := client.Assets.CreateFolder(platform.CreateFolderXQuery{Name: "dir"})
+	if err == nil || calls != 1 {
+		t.Fatalf("Failed ! expected a single failed attempt, got %d attempts and err %v", calls, err)
+	}
+
+	ctx := platform.WithIdempotencyKey(context.Background(), "create-dir-1")
+	_, err = client.Assets.CreateFolderWithContext(ctx, platform.CreateFolderXQuery{Name: "dir"})
+	if err == nil || calls != 4 {
+		t.Fatalf("Failed ! expected 3 more attempts, got %d attempts and err %v", calls, err)
+	}
+	if keys[len(keys)-1] != "create-dir-1" {
+		t.Errorf("Failed ! expected idempotency key header, got %q", keys[len(keys)-1])
+	}
+}
+
+// failingTransport fails requests with errs, one per request, then sends them through http.DefaultTransport
+type failingTransport struct {
+	mu    sync.Mutex
+	errs  []error
+	calls int
+}
+
+func (f *failingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	f.mu.Lock()
+	f.calls++
+	if len(f.errs) > 0 {
+		err := f.errs[0]
+		f.errs = f.errs[1:]
+		f.mu.Unlock()
+		return nil, err
+	}
+	f.mu.Unlock()
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func TestRetryPolicyRetriesOnlyTransientTransportErrors(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"items":[],"page":{"type":"number","current":1,"hasNext":false}}`)
+	}))
+	defer srv.Close()
+
+	reset := &net.OpError{Op: "read", Net: "tcp", Err: os.NewSyscallError("read", syscall.ECONNRESET)}
+	transport := &failingTransport{errs: []error{reset, reset}}
+	client := newPixelbinFor(srv, transport)
+	client.Config.SetRetryPolicy(&platform.RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond})
+	if _, err := client.Assets.ListFiles(platform.ListFilesXQuery{}); err != nil || transport.calls != 3 {
+		t.Errorf("Failed ! expected connection resets to be retried, got %d attempts and err %v", transport.calls, err)
+	}
+
+	transport = &failingTransport{errs: []error{x509.UnknownAuthorityError{}, x509.UnknownAuthorityError{}}}
+	client = newPixelbinFor(srv, transport)
+	client.Config.SetRetryPolicy(&platform.RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond})
+	if _, err := client.Assets.ListFiles(platform.ListFilesXQuery{}); err == nil || transport.calls != 1 {
+		t.Errorf("Failed ! expected a TLS failure not to be retried, got %d attempts and err %v", transport.calls, err)
+	}
+}
+
+func TestMiddlewareSeesEveryCall(t *testing.T) {
+	client, srv := newLocalPixelbin(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Tenant-Id") != "tenant-42" {
+			w.WriteHeader(http.StatusUnauthorized)
+			fmt.Fprint(w, `{"message":"missing tenant"}`)
+			return
+		}
+		if r.URL.Path == "/service/platform/assets/v1.0/presets/missing" {
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"message":"preset not found"}`)
+			return
+		}
+		fmt.Fprint(w, `{"presetName":"p1"}`)
+	})
+	defer srv.Close()
+
+	var order []string
+	var seen []string
+	client.Config.Use(
+		func(next platform.Handler) platform.Handler {
+			return func(ctx context.Context, req *platform.Request) (*platform.Response, error) {
+				order = append(order, "outer")
+				res, err := next(ctx, req)
+				seen = append(seen, fmt.Sprintf("%s %s %d %v", req.Method, req.Path, res.StatusCode, err != nil))
+				return res, err
+			}
+		},
+		func(next platform.Handler) platform.Handler {
+			return func(ctx context.Context, req *platform.Request) (*platform.Response, error) {
+				order = append(order, "inner")
+				req.Header["X-Tenant-Id"] = "tenant-42"
+				return next(ctx, req)
+			}
+		},
+	)
+
+	if _, err := client.Assets.GetPreset(platform.GetPresetXQuery{PresetName: "p1"}); err != nil {
+		t.Fatalf("Failed ! got err %v", err)
+	}
+	if _, err := client.Assets.GetPreset(platform.GetPresetXQuery{PresetName: "missing"}); err == nil {
+		t.Fatalf("Failed ! expected an error for a missing preset")
+	}
+
+	if strings.Join(order, ",") != "outer,inner,outer,inner" {
+		t.Errorf("Failed ! unexpected middleware order %v", order)
+	}
+	expected := []string{
+		"GET /service/platform/assets/v1.0/presets/p1 200 false",
+		"GET /service/platform/assets/v1.0/presets/missing 404 true",
+	}
+	if strings.Join(seen, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("Failed ! expected %v got %v", expected, seen)
+	}
+}
+
+func TestTypedResponses(t *testing.T) {
+	client, srv := newLocalPixelbin(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/service/platform/assets/v1.0/listFiles":
+			fmt.Fprint(w, `{"items":[{"_id":"1","name":"dir","type":"folder"},{"_id":"2","name":"asset1","type":"file","path":"dir","fileId":"dir/asset1","format":"jpeg","size":1000,"access":"private"}],"page":{"type":"number","size":2,"current":1,"hasNext":true}}`)
+		case "/service/platform/assets/v1.0/files/delete":
+			fmt.Fprint(w, `[{"_id":"2","name":"asset1","fileId":"dir/asset1","tags":["a"]}]`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"message":"not found"}`)
+		}
+	})
+	defer srv.Close()
+
+	list, err := client.Assets.ListFilesTyped(platform.ListFilesXQuery{Path: "dir"})
+	if err != nil {
+		t.Fatalf("Failed ! got err %v", err)
+	}
+	if len(list.Items) != 2 || list.Items[1].FileId != "dir/asset1" || list.Items[1].Access != platform.PRIVATE || !list.Page.HasNext {
+		t.Errorf("Failed ! unexpected typed response %+v", list)
+	}
+
+	deleted, err := client.Assets.DeleteFilesTyped(platform.DeleteFilesXQuery{Ids: []string{"2"}})
+	if err != nil {
+		t.Fatalf("Failed ! got err %v", err)
+	}
+	if len(deleted) != 1 || deleted[0].Tags[0] != "a" {
+		t.Errorf("Failed ! unexpected typed response %+v", deleted)
+	}
+}
+
+func TestCreateFolderTyped(t *testing.T) {
+	client, srv := newLocalPixelbin(func(w http.ResponseWriter, r *http.Request) {
+		var body platform.CreateFolderRequest
+		json.NewDecoder(r.Body).Decode(&body)
+		if r.Method != http.MethodPost || r.URL.Path != "/service/platform/assets/v1.0/folders" || body.Name != "folder" || body.Path != "nested" {
+			t.Errorf("Failed ! unexpected request %s %s %+v", r.Method, r.URL.Path, body)
+		}
+		fmt.Fprint(w, `{"_id":"f1","name":"folder","path":"nested","isActive":true}`)
+	})
+	defer srv.Close()
+
+	folder, err := client.Assets.CreateFolderTyped(platform.CreateFolderXQuery{Name: "folder", Path: "nested"})
+	if err != nil {
+		t.Fatalf("Failed ! got err %v", err)
+	}
+	expected := platform.FoldersResponse{ID: "f1", Name: "folder", Path: "nested", IsActive: true}
+	if *folder != expected {
+		t.Errorf("Failed ! expected %+v, got %+v", expected, *folder)
+	}
+}
+
+func TestMalformedSuccessBodyIsAnFDKError(t *testing.T) {
+	client, srv := newLocalPixelbin(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"_id":`)
+	})
+	defer srv.Close()
+
+	_, err := client.Assets.CreateFolderTyped(platform.CreateFolderXQuery{Name: "folder"})
+	var fdkErr *common.FDKError
+	if !errors.As(err, &fdkErr) {
+		t.Fatalf("Failed ! expected an FDKError, got %T %v", err, err)
+	}
+	var syntaxErr *json.SyntaxError
+	if !errors.As(err, &syntaxErr) {
+		t.Errorf("Failed ! expected the JSON decoding error as cause, got %v", err)
+	}
+}
+
+func TestErrorsCarryStatusAndMatchSentinels(t *testing.T) {
+	client, srv := newLocalPixelbin(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/service/platform/assets/v1.0/files/missing":
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"message":"File not found"}`)
+		case "/service/platform/assets/v1.0/folders":
+			w.WriteHeader(http.StatusConflict)
+			fmt.Fprint(w, `{"message":"Folder already exists"}`)
+		default:
+			w.Header().Set("X-Upstream", "lb")
+			w.WriteHeader(http.StatusBadGateway)
+			fmt.Fprint(w, "<html>Bad Gateway</html>")
+		}
+	})
+	defer srv.Close()
+
+	_, err := client.Assets.GetFileByFileId(platform.GetFileByFileIdXQuery{FileId: "missing"})
+	if !common.IsNotFound(err) || common.IsConflict(err) || !errors.Is(err, common.ErrNotFound) {
+		t.Errorf("Failed ! expected a not found error, got %v", err)
+	}
+	if err == nil || err.Error() != "File not found" {
+		t.Errorf("Failed ! expected the API message, got %v", err)
+	}
+
+	_, err = client.Assets.CreateFolder(platform.CreateFolderXQuery{Name: "dir"})
+	if !common.IsConflict(err) {
+		t.Errorf("Failed ! expected a conflict error, got %v", err)
+	}
+
+	_, err = client.Assets.ListFiles(platform.ListFilesXQuery{})
+	var fdkErr *common.FDKError
+	if !errors.As(err, &fdkErr) {
+		t.Fatalf("Failed ! expected an FDKError for a non-JSON body, got %T %v", err, err)
+	}
+	if fdkErr.Status != http.StatusBadGateway || string(fdkErr.Body) != "<html>Bad Gateway</html>" || fdkErr.Header.Get("X-Upstream") != "lb" {
+		t.Errorf("Failed ! expected status, body and headers to be kept, got %d %q %v", fdkErr.Status, fdkErr.Body, fdkErr.Header)
+	}
+	if cause := errors.Unwrap(err); cause != nil {
+		t.Errorf("Failed ! expected no cause for an HTTP failure, got %v", cause)
+	}
+}
+
+func TestSuccessStatusesAndEmptyBodies(t *testing.T) {
+	client, srv := newLocalPixelbin(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodPost:
+			w.WriteHeader(http.StatusCreated)
+			fmt.Fprint(w, `{"_id":"f1","name":"dir","path":"","isActive":true}`)
+		case http.MethodDelete:
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.WriteHeader(http.StatusAccepted)
+		}
+	})
+	defer srv.Close()
+
+	folder, err := client.Assets.CreateFolderTyped(platform.CreateFolderXQuery{Name: "dir"})
+	if err != nil || folder.ID != "f1" {
+		t.Fatalf("Failed ! expected 201 to succeed, got %+v %v", folder, err)
+	}
+
+	deleted, err := client.Assets.DeleteFile(platform.DeleteFileXQuery{FileId: "dir/asset"})
+	if err != nil || deleted == nil || len(deleted) != 0 {
+		t.Errorf("Failed ! expected an empty result for 204, got %v %v", deleted, err)
+	}
+	typed, err := client.Assets.DeleteFolderTyped(platform.DeleteFolderXQuery{ID: "f1"})
+	if err != nil || typed == nil {
+		t.Errorf("Failed ! expected an empty typed result for 204, got %v %v", typed, err)
+	}
+
+	_, err = client.Assets.GetFileById(platform.GetFileByIdXQuery{ID: "f1"})
+	if err != nil {
+		t.Errorf("Failed ! expected 202 with an empty body to succeed, got %v", err)
+	}
+}
+
+func TestFileUploadStreamsMultipartBody(t *testing.T) {
+	content := strings.Repeat("pixelbin", 64*1024)
+	file, err := os.CreateTemp(t.TempDir(), "upload-*.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	if _, err = file.WriteString(content); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = file.Seek(0, io.SeekStart); err != nil {
+		t.Fatal(err)
+	}
+
+	client, srv := newLocalPixelbin(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			t.Errorf("Failed ! reading body: %v", err)
+			return
+		}
+		if r.ContentLength != int64(len(body)) {
+			t.Errorf("Failed ! Content-Length %d does not match body size %d", r.ContentLength, len(body))
+		}
+		mediaType, params, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+		if err != nil || mediaType != "multipart/form-data" {
+			t.Errorf("Failed ! unexpected Content-Type %q", r.Header.Get("Content-Type"))
+			return
+		}
+		form, err := multipart.NewReader(bytes.NewReader(body), params["boundary"]).ReadForm(1 << 20)
+		if err != nil {
+			t.Errorf("Failed ! parsing multipart body: %v", err)
+			return
+		}
+		if got := form.Value["tags"]; len(got) != 2 || got[0] != "a" || got[1] != "b" {
+			t.Errorf("Failed ! unexpected tags %v", got)
+		}
+		if got := form.Value["name"]; len(got) != 1 || got[0] != "streamed" {
+			t.Errorf("Failed ! unexpected name %v", got)
+		}
+		uploaded, err := form.File["file"][0].Open()
+		if err != nil {
+			t.Errorf("Failed ! opening file part: %v", err)
+			return
+		}
+		defer uploaded.Close()
+		data, _ := io.ReadAll(uploaded)
+		if string(data) != content {
+			t.Errorf("Failed ! file part holds %d bytes, want %d", len(data), len(content))
+		}
+		fmt.Fprint(w, `{"name":"streamed"}`)
+	})
+	defer srv.Close()
+
+	_, err = client.Assets.FileUpload(platform.FileUploadXQuery{File: file, Name: "streamed", Tags: []string{"a", "b"}})
+	if err != nil {
+		t.Fatalf("Failed ! %v", err)
+	}
+}
+
+func TestFileUploadFromReader(t *testing.T) {
+	type part struct {
+		filename      string
+		content       string
+		contentLength int64
+	}
+	parts := make(chan part, 1)
+	client, srv := newLocalPixelbin(func(w http.ResponseWriter, r *http.Request) {
+		file, header, err := r.FormFile("file")
+		if err != nil {
+			t.Errorf("Failed ! reading file part: %v", err)
+			return
+		}
+		defer file.Close()
+		data, _ := io.ReadAll(file)
+		parts <- part{filename: header.Filename, content: string(data), contentLength: r.ContentLength}
+		fmt.Fprint(w, `{"name":"generated"}`)
+	})
+	defer srv.Close()
+
+	_, err := client.Assets.FileUpload(platform.FileUploadXQuery{
+		File:     bytes.NewBufferString("in-memory image"),
+		FileName: "generated.png",
+	})
+	if err != nil {
+		t.Fatalf("Failed ! %v", err)
+	}
+	got := <-parts
+	if got.filename != "generated.png" || got.content != "in-memory image" {
+		t.Errorf("Failed ! unexpected file part %q with %q", got.filename, got.content)
+	}
+	if got.contentLength <= 0 {
+		t.Errorf("Failed ! expected a Content-Length for a sized reader, got %d", got.contentLength)
+	}
+
+	_, err = client.Assets.FileUpload(platform.FileUploadXQuery{
+		File:     io.MultiReader(strings.NewReader("first "), strings.NewReader("second")),
+		FileName: "joined.txt",
+	})
+	if err != nil {
+		t.Fatalf("Failed ! %v", err)
+	}
+	got = <-parts
+	if got.content != "first second" {
+		t.Errorf("Failed ! unexpected content %q", got.content)
+	}
+	if got.contentLength != -1 {
+		t.Errorf("Failed ! expected chunked encoding for an unsized reader, got Content-Length %d", got.contentLength)
+	}
+}
+
+func TestArrayQueryParamsAreRepeated(t *testing.T) {
+	queries := make(chan url.Values, 2)
+	client, srv := newLocalPixelbin(func(w http.ResponseWriter, r *http.Request) {
+		queries <- r.URL.Query()
+		fmt.Fprint(w, `{}`)
+	})
+	defer srv.Close()
+	var seen *platform.Request
+	client.Config.Use(func(next platform.Handler) platform.Handler {
+		return func(ctx context.Context, req *platform.Request) (*platform.Response, error) {
+			seen = req
+			return next(ctx, req)
+		}
+	})
+
+	_, err := client.Assets.ListFiles(platform.ListFilesXQuery{Tags: []interface{}{"cat", "dog"}, Sort: "name"})
+	if err != nil {
+		t.Fatalf("Failed ! %v", err)
+	}
+	if seen.Query["sort"] != "name" || len(seen.QueryValues["tags"]) != 2 {
+		t.Errorf("Failed ! expected middleware to see sort in Query and tags in QueryValues, got %v and %v", seen.Query, seen.QueryValues)
+	}
+	q := <-queries
+	if tags := q["tags"]; len(tags) != 2 || tags[0] != "cat" || tags[1] != "dog" {
+		t.Errorf("Failed ! expected tags=cat&tags=dog, got %v", tags)
+	}
+	if q.Get("sort") != "name" {
+		t.Errorf("Failed ! expected sort=name, got %v", q["sort"])
+	}
+
+	_, err = client.Assets.GetPresets(platform.GetPresetsXQuery{Sort: []interface{}{"-updatedAt", "name"}})
+	if err != nil {
+		t.Fatalf("Failed ! %v", err)
+	}
+	q = <-queries
+	if sort := q["sort"]; len(sort) != 2 || sort[0] != "-updatedAt" || sort[1] != "name" {
+		t.Errorf("Failed ! expected two sort keys, got %v", sort)
+	}
+}
+
+func TestListFilesPaginatorWalksEveryPage(t *testing.T) {
+	var requests int32
+	client, srv := newLocalPixelbin(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		if r.URL.Query().Get("path") != "dir" {
+			t.Errorf("Failed ! filters were not kept across pages: %v", r.URL.Query())
+		}
+		switch r.URL.Query().Get("pageNo") {
+		case "1":
+			fmt.Fprint(w, `{"items":[{"name":"a"},{"name":"b"}],"page":{"current":1,"hasNext":true}}`)
+		case "2":
+			fmt.Fprint(w, `{"items":[{"name":"c"}],"page":{"current":2,"hasNext":false}}`)
+		default:
+			t.Errorf("Failed ! unexpected page %q", r.URL.Query().Get("pageNo"))
+		}
+	})
+	defer srv.Close()
+
+	it := client.Assets.ListFilesPaginator(platform.ListFilesXQuery{Path: "dir"})
+	var names []string
+	for it.Next() {
+		names = append(names, it.Item().Name)
+	}
+	if err := it.Err(); err != nil {
+		t.Fatalf("Failed ! %v", err)
+	}
+	if strings.Join(names, ",") != "a,b,c" {
+		t.Errorf("Failed ! got items %v", names)
+	}
+	if requests != 2 {
+		t.Errorf("Failed ! expected 2 page requests, got %d", requests)
+	}
+}
+
+func TestListFilesPaginatorStopsOnErrorAndCancel(t *testing.T) {
+	client, srv := newLocalPixelbin(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("pageNo") == "1" {
+			fmt.Fprint(w, `{"items":[{"name":"a"}],"page":{"current":1,"hasNext":true}}`)
+			return
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+		fmt.Fprint(w, `{"message":"boom"}`)
+	})
+	defer srv.Close()
+
+	it := client.Assets.ListFilesPaginator(platform.ListFilesXQuery{})
+	count := 0
+	for it.Next() {
+		count++
+	}
+	if count != 1 || common.StatusCode(it.Err()) != http.StatusInternalServerError {
+		t.Errorf("Failed ! expected one item then a 500, got %d items and %v", count, it.Err())
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	it = client.Assets.ListFilesPaginatorWithContext(ctx, platform.ListFilesXQuery{})
+	if !it.Next() {
+		t.Fatalf("Failed ! %v", it.Err())
+	}
+	cancel()
+	if it.Next() || !errors.Is(it.Err(), context.Canceled) {
+		t.Errorf("Failed ! expected iteration to stop with context.Canceled, got %v", it.Err())
+	}
+}
+
+func TestGetPresetsPaginatorKeepsFilters(t *testing.T) {
+	client, srv := newLocalPixelbin(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if q.Get("name") != "thumb" || q.Get("transformation") != "t.resize()" || q.Get("archived") != "true" {
+			t.Errorf("Failed ! filters were not kept across pages: %v", q)
+		}
+		switch q.Get("pageNo") {
+		case "3":
+			fmt.Fprint(w, `{"items":[{"presetName":"thumb-1"}],"page":{"current":3,"hasNext":true}}`)
+		case "4":
+			fmt.Fprint(w, `{"items":[{"presetName":"thumb-2"}],"page":{"current":4,"hasNext":false}}`)
+		default:
+			t.Errorf("Failed ! unexpected page %q", q.Get("pageNo"))
+		}
+	})
+	defer srv.Close()
+
+	it := client.Assets.GetPresetsPaginator(platform.GetPresetsXQuery{
+		PageNo:         3,
+		Name:           "thumb",
+		Transformation: "t.resize()",
+		Archived:       true,
+	})
+	var names []string
+	for it.Next() {
+		names = append(names, it.Item().PresetName)
+	}
+	if err := it.Err(); err != nil {
+		t.Fatalf("Failed ! %v", err)
+	}
+	if strings.Join(names, ",") != "thumb-1,thumb-2" {
+		t.Errorf("Failed ! got presets %v", names)
+	}
+}
+
+// fakeTreeServer answers ListFiles for a small folder tree, failing for the folder "broken"
+func fakeTreeServer() (*platform.PixelbinClient, *httptest.Server) {
+	tree := map[string]string{
+		"":      `[{"name":"a","type":"folder"},{"name":"f1","type":"file"},{"name":"broken","type":"folder"}]`,
+		"a":     `[{"name":"b","type":"folder","path":"a"},{"name":"f2","type":"file","path":"a"}]`,
+		"a/b":   `[{"name":"f3","type":"file","path":"a/b"}]`,
+		"empty": `[]`,
+	}
+	return newLocalPixelbin(func(w http.ResponseWriter, r *http.Request) {
+		items, ok := tree[r.URL.Query().Get("path")]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"message":"folder not found"}`)
+			return
+		}
+		writeListFilesPage(w, items)
+	})
+}
+
+func TestWalkOrders(t *testing.T) {
+	client, srv := fakeTreeServer()
+	defer srv.Close()
+
+	for _, tc := range []struct {
+		order platform.WalkOrder
+		want  string
+	}{
+		{platform.DepthFirst, "a/1 a/b/2 a/b/f3/3 a/f2/2 f1/1 broken/1 broken!"},
+		{platform.BreadthFirst, "a/1 f1/1 broken/1 broken! a/b/2 a/f2/2 a/b/f3/3"},
+	} {
+		var visited []string
+		err := client.Assets.Walk("", func(entry platform.WalkEntry, err error) error {
+			if err != nil {
+				if !common.IsNotFound(err) {
+					t.Errorf("Failed ! unexpected listing error %v", err)
+				}
+				visited = append(visited, entry.Path+"!")
+				return nil
+			}
+			visited = append(visited, fmt.Sprintf("%s/%d", entry.Path, entry.Depth))
+			return nil
+		}, platform.WithWalkOrder(tc.order), platform.WithWalkConcurrency(2))
+		if err != nil {
+			t.Fatalf("Failed ! %v", err)
+		}
+		if got := strings.Join(visited, " "); got != tc.want {
+			t.Errorf("Failed ! order %d visited %q, want %q", tc.order, got, tc.want)
+		}
+	}
+}
+
+func TestWalkSkipDirAndStop(t *testing.T) {
+	client, srv := fakeTreeServer()
+	defer srv.Close()
+
+	var visited []string
+	err := client.Assets.Walk("", func(entry platform.WalkEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		visited = append(visited, entry.Path)
+		if entry.Path == "a" || entry.Path == "f1" {
+			return fs.SkipDir
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Failed ! %v", err)
+	}
+	if got := strings.Join(visited, " "); got != "a f1" {
+		t.Errorf("Failed ! visited %q", got)
+	}
+
+	stop := errors.New("stop")
+	err = client.Assets.Walk("a", func(entry platform.WalkEntry, err error) error {
+		if entry.Path == "a/b/f3" {
+			return stop
+		}
+		return nil
+	})
+	if !errors.Is(err, stop) {
+		t.Errorf("Failed ! expected the walk to stop with fn's error, got %v", err)
+	}
+}
+
+func TestWalkListsAtMostConcurrencyFoldersAhead(t *testing.T) {
+	var mu sync.Mutex
+	var listed []string
+	client, srv := newLocalPixelbin(func(w http.ResponseWriter, r *http.Request) {
+		folder := r.URL.Query().Get("path")
+		mu.Lock()
+		listed = append(listed, folder)
+		mu.Unlock()
+		items := `[]`
+		if folder == "" {
+			var folders []string
+			for i := 0; i < 20; i++ {
+				folders = append(folders, fmt.Sprintf(`{"name":"d%02d","type":"folder"}`, i))
+			}
+			items = "[" + strings.Join(folders, ",") + "]"
+		}
+		writeListFilesPage(w, items)
+	})
+	defer srv.Close()
+
+	listedWhileBusy := 0
+	err := client.Assets.Walk("", func(entry platform.WalkEntry, err error) error {
+		if entry.Path == "d00" {
+			// leave the walker time to list as far ahead as it will
+			time.Sleep(100 * time.Millisecond)
+			mu.Lock()
+			listedWhileBusy = len(listed)
+			mu.Unlock()
+		}
+		if entry.Path < "d10" {
+			return fs.SkipDir
+		}
+		return nil
+	}, platform.WithWalkConcurrency(2))
+	if err != nil {
+		t.Fatalf("Failed ! %v", err)
+	}
+	// the root, then two folders ahead
+	if listedWhileBusy != 3 {
+		t.Errorf("Failed ! expected 3 listings while the callback was busy, got %d", listedWhileBusy)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	for _, folder := range []string{"d10", "d19"} {
+		found := false
+		for _, l := range listed {
+			found = found || l == folder
+		}
+		if !found {
+			t.Errorf("Failed ! expected %s to be listed, got %v", folder, listed)
+		}
+	}
+}
+
+// fakeStorageServer serves ListFiles, GetFileByFileId and file downloads for a small organization
+func fakeStorageServer(t *testing.T) *httptest.Server {
+	listings := map[string]string{
+		"":           `[{"name":"docs","type":"folder"},{"name":"logo","type":"file","fileId":"logo","format":"png","size":4}]`,
+		"docs":       `[{"name":"readme","type":"file","path":"docs","fileId":"docs/readme","format":"txt","size":11},{"name":"empty","type":"folder","path":"docs"}]`,
+		"docs/empty": `[]`,
+	}
+	contents := map[string]string{"logo": "\x89PNG", "docs/readme": "hello world"}
+	var srv *httptest.Server
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/service/platform/assets/v1.0/listFiles":
+			items, ok := listings[r.URL.Query().Get("path")]
+			if !ok {
+				items = `[]`
+			}
+			writeListFilesPage(w, items)
+		case strings.HasPrefix(r.URL.Path, "/service/platform/assets/v1.0/files/"):
+			fileId := strings.TrimPrefix(r.URL.Path, "/service/platform/assets/v1.0/files/")
+			fmt.Fprintf(w, `{"fileId":%q,"url":"%s/cdn/%s"}`, fileId, srv.URL, fileId)
+		case strings.HasPrefix(r.URL.Path, "/cdn/"):
+			content, ok := contents[strings.TrimPrefix(r.URL.Path, "/cdn/")]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			fmt.Fprint(w, content)
+		default:
+			t.Errorf("Failed ! unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	return srv
+}
+
+func TestAssetsFS(t *testing.T) {
+	srv := fakeStorageServer(t)
+	defer srv.Close()
+	fsys := newPixelbinFor(srv, nil).Assets.FS()
+
+	if err := fstest.TestFS(fsys, "logo.png", "docs/readme.txt", "docs/empty"); err != nil {
+		t.Fatalf("Failed ! %v", err)
+	}
+
+	content, err := fs.ReadFile(fsys, "docs/readme.txt")
+	if err != nil || string(content) != "hello world" {
+		t.Errorf("Failed ! read %q, %v", content, err)
+	}
+	matches, err := fs.Glob(fsys, "*/*.txt")
+	if err != nil || strings.Join(matches, ",") != "docs/readme.txt" {
+		t.Errorf("Failed ! glob matched %v, %v", matches, err)
+	}
+	if _, err = fs.Stat(fsys, "docs/missing.txt"); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("Failed ! expected fs.ErrNotExist, got %v", err)
+	}
+}
+
+func TestAssetsFSCachesListingsAndSignsPrivateAssets(t *testing.T) {
+	var mu sync.Mutex
+	listed := map[string]int{}
+	var downloaded *url.URL
+	var srvURL string
+	client, srv := newLocalPixelbin(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/service/platform/assets/v1.0/listFiles":
+			folder := r.URL.Query().Get("path")
+			mu.Lock()
+			listed[folder]++
+			mu.Unlock()
+			items := `[]`
+			if folder == "" {
+				var files []string
+				for i := 0; i < 10; i++ {
+					files = append(files, fmt.Sprintf(`{"name":"f%d","type":"file","fileId":"f%d","format":"txt","size":6}`, i, i))
+				}
+				files = append(files, `{"name":"secret","type":"file","fileId":"secret","format":"txt","size":6,"access":"private"}`)
+				items = "[" + strings.Join(files, ",") + "]"
+			}
+			writeListFilesPage(w, items)
+		case strings.HasPrefix(r.URL.Path, "/service/platform/assets/v1.0/files/"):
+			fileId := strings.TrimPrefix(r.URL.Path, "/service/platform/assets/v1.0/files/")
+			fmt.Fprintf(w, `{"fileId":%q,"access":"private","url":"%s/cdn/%s"}`, fileId, srvURL, fileId)
+		case r.URL.Path == "/cdn/secret":
+			mu.Lock()
+			downloaded = r.URL
+			mu.Unlock()
+			fmt.Fprint(w, "hidden")
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+	defer srv.Close()
+	srvURL = srv.URL
+
+	if _, err := fs.ReadFile(client.Assets.FS(), "secret.txt"); err == nil {
+		t.Errorf("Failed ! expected a private asset to need signing")
+	}
+
+	fsys := client.Assets.FS(platform.WithFSSigning("access-key", "token", 60))
+	for i := 0; i < 10; i++ {
+		if _, err := fs.Stat(fsys, fmt.Sprintf("f%d.txt", i)); err != nil {
+			t.Fatalf("Failed ! %v", err)
+		}
+	}
+	content, err := fs.ReadFile(fsys, "secret.txt")
+	if err != nil || string(content) != "hidden" {
+		t.Fatalf("Failed ! read %q, %v", content, err)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	// one listing for the unsigned view, one for the signed one
+	if listed[""] != 2 {
+		t.Errorf("Failed ! expected the root to be listed once per FS, got %d listings", listed[""])
+	}
+	if downloaded.Query().Get("pbs") == "" || downloaded.Query().Get("pbt") != "access-key" {
+		t.Errorf("Failed ! expected a signed download url, got %s", downloaded)
+	}
+}
+
+// fakeSyncServer holds a remote folder "site" and records signed-url and delete requests
+type fakeSyncServer struct {
+	*httptest.Server
+	mu       sync.Mutex
+	uploads  []string
+	metadata map[string]interface{}
+	deleted  []string
+	// kept lists the ids the delete endpoint leaves out of its response
+	kept        map[string]bool
+	deleteCalls int
+}
+
+func newFakeSyncServer(t *testing.T) *fakeSyncServer {
+	f := &fakeSyncServer{}
+	f.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/service/platform/assets/v1.0/listFiles":
+			items := `[]`
+			if r.URL.Query().Get("path") == "site" {
+				items = `[{"_id":"id-same","name":"same","type":"file","path":"site","fileId":"site/same","format":"jpeg","size":4},
+					{"_id":"id-changed","name":"changed","type":"file","path":"site","fileId":"site/changed","format":"txt","size":1},
+					{"_id":"id-extra","name":"extra","type":"file","path":"site","fileId":"site/extra","format":"png","size":9}]`
+			}
+			writeListFilesPage(w, items)
+		case r.URL.Path == "/service/platform/assets/v2.0/upload/signed-url":
+			var body struct {
+				Name, Path, Format string
+				Overwrite          bool
+				Metadata           map[string]interface{}
+			}
+			json.NewDecoder(r.Body).Decode(&body)
+			f.mu.Lock()
+			f.uploads = append(f.uploads, fmt.Sprintf("%s/%s.%s", body.Path, body.Name, body.Format))
+			f.metadata = body.Metadata
+			f.mu.Unlock()
+			if !body.Overwrite {
+				t.Errorf("Failed ! sync uploads must overwrite")
+			}
+			fmt.Fprintf(w, `{"presignedUrl":{"url":"%s/upload","fields":{}}}`, f.URL)
+		case r.URL.Path == "/upload" && r.Method == http.MethodPut:
+			w.WriteHeader(http.StatusNoContent)
+		case r.URL.Path == "/upload" && r.Method == http.MethodPost:
+			fmt.Fprint(w, `{}`)
+		case r.URL.Path == "/service/platform/assets/v1.0/files/delete":
+			var body struct{ Ids []string }
+			json.NewDecoder(r.Body).Decode(&body)
+			f.mu.Lock()
+			defer f.mu.Unlock()
+			f.deleteCalls++
+			var files []string
+			for _, id := range body.Ids {
+				if !f.kept[id] {
+					f.deleted = append(f.deleted, id)
+					files = append(files, fmt.Sprintf(`{"_id":%q}`, id))
+				}
+			}
+			fmt.Fprintf(w, `[%s]`, strings.Join(files, ","))
+		default:
+			t.Errorf("Failed ! unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	return f
+}
+
+func TestSyncUploadsChangesAndDeletesExtras(t *testing.T) {
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		"same.jpg":       "same",
+		"changed.txt":    "now longer",
+		"new.png":        "new",
+		"img/nested.png": "nested",
+	} {
+		p := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	srv := newFakeSyncServer(t)
+	defer srv.Close()
+	client := newPixelbinFor(srv.Server, nil)
+
+	report, err := client.Sync(dir, "site", platform.SyncOptions{Delete: true, DryRun: true})
+	if err != nil {
+		t.Fatalf("Failed ! %v", err)
+	}
+	want := "update changed.txt (10 bytes)\ndelete extra.png (9 bytes)\nupload img/nested.png (6 bytes)\nupload new.png (3 bytes)\nskip same.jpg (4 bytes)\n"
+	if report.String() != want {
+		t.Errorf("Failed ! dry-run report:\n%s\nwant:\n%s", report, want)
+	}
+	if len(srv.uploads) != 0 || len(srv.deleted) != 0 {
+		t.Errorf("Failed ! dry-run changed the remote folder: %v %v", srv.uploads, srv.deleted)
+	}
+
+	report, err = client.Sync(dir, "site", platform.SyncOptions{
+		Delete:        true,
+		UploadOptions: []platform.UploaderOption{platform.WithChunkSize(4)},
+	})
+	if err != nil {
+		t.Fatalf("Failed ! %v", err)
+	}
+	sort.Strings(srv.uploads)
+	if got := strings.Join(srv.uploads, " "); got != "site/changed.txt site/img/nested.png site/new.png" {
+		t.Errorf("Failed ! uploaded %q", got)
+	}
+	if strings.Join(srv.deleted, " ") != "id-extra" {
+		t.Errorf("Failed ! deleted %v", srv.deleted)
+	}
+	if sum, _ := srv.metadata[platform.SyncChecksumMetadataKey].(string); len(sum) != 64 {
+		t.Errorf("Failed ! expected a sha256 in the upload metadata, got %v", srv.metadata)
+	}
+	if report.Count(platform.SyncUpload) != 2 || report.Count(platform.SyncSkip) != 1 {
+		t.Errorf("Failed ! unexpected report:\n%s", report)
+	}
+}
+
+func TestSyncDeletesInBatchesAndReportsKeptFiles(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "changed.txt"), []byte("c"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	srv := newFakeSyncServer(t)
+	defer srv.Close()
+	srv.kept = map[string]bool{"id-extra": true}
+	client := newPixelbinFor(srv.Server, nil)
+
+	report, err := client.Sync(dir, "site", platform.SyncOptions{Delete: true, DeleteOptions: platform.BulkOptions{BatchSize: 1}})
+	if !errors.Is(err, platform.ErrNotDeleted) {
+		t.Fatalf("Failed ! expected ErrNotDeleted, got %v", err)
+	}
+	if srv.deleteCalls != 2 || strings.Join(srv.deleted, " ") != "id-same" {
+		t.Errorf("Failed ! expected 2 delete calls removing id-same, got %d removing %v", srv.deleteCalls, srv.deleted)
+	}
+	failed := report.Failed()
+	if len(failed) != 1 || failed[0].Path != "extra.png" {
+		t.Errorf("Failed ! unexpected report:\n%s", report)
+	}
+}
+
+func TestSyncReportsLocalFilesMappingToTheSameAsset(t *testing.T) {
+	dir := t.TempDir()
+	for name, content := range map[string]string{"same.jpg": "same", "same.jpeg": "SAME", "new.png": "new"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	srv := newFakeSyncServer(t)
+	defer srv.Close()
+	client := newPixelbinFor(srv.Server, nil)
+
+	report, err := client.Sync(dir, "site", platform.SyncOptions{Delete: true})
+	if !errors.Is(err, platform.ErrSyncCollision) {
+		t.Fatalf("Failed ! expected ErrSyncCollision, got %v", err)
+	}
+	failed := report.Failed()
+	if len(failed) != 2 || failed[0].Path != "same.jpeg" || failed[1].Path != "same.jpg" || failed[0].FileId != "site/same" {
+		t.Errorf("Failed ! expected both colliding files to fail, got:\n%s", report)
+	}
+	if got := strings.Join(srv.uploads, " "); got != "site/new.png" {
+		t.Errorf("Failed ! expected only new.png to be uploaded, got %q", got)
+	}
+	// the asset both files map to is kept, the other remote files are deleted
+	sort.Strings(srv.deleted)
+	if got := strings.Join(srv.deleted, " "); got != "id-changed id-extra" {
+		t.Errorf("Failed ! deleted %q", got)
+	}
+}
+
+func TestSyncFailsWhenASubfolderCannotBeListed(t *testing.T) {
+	client, srv := newLocalPixelbin(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/service/platform/assets/v1.0/listFiles" {
+			t.Errorf("Failed ! unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		switch r.URL.Query().Get("path") {
+		case "site":
+			writeListFilesPage(w, `[{"name":"gone","type":"folder","path":"site"},
+				{"_id":"id-logo","name":"logo","type":"file","path":"site","fileId":"site/logo","format":"png","size":4}]`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"message":"folder not found"}`)
+		}
+	})
+	defer srv.Close()
+	dir := t.TempDir()
+
+	// a missing root folder has no files
+	report, err := client.Sync(dir, "missing", platform.SyncOptions{Delete: true})
+	if err != nil || len(report.Changes) != 0 {
+		t.Errorf("Failed ! expected nothing to sync into a missing folder, got %v\n%s", err, report)
+	}
+
+	// a missing subfolder leaves the listing incomplete, nothing may be deleted or downloaded
+	if _, err = client.Sync(dir, "site", platform.SyncOptions{Delete: true}); !common.IsNotFound(err) {
+		t.Errorf("Failed ! expected the subfolder listing error, got %v", err)
+	}
+	if _, err = client.Download("site", dir, platform.DownloadOptions{}); !common.IsNotFound(err) {
+		t.Errorf("Failed ! expected the subfolder listing error, got %v", err)
+	}
+}
+
+func TestDownloadMirrorsFolderAndSignsPrivateAssets(t *testing.T) {
+	var srv *httptest.Server
+	var downloads int32
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/service/platform/assets/v1.0/listFiles":
+			items := `[]`
+			switch r.URL.Query().Get("path") {
+			case "media":
+				items = `[{"name":"logo","type":"file","path":"media","fileId":"media/logo","format":"png","size":4,"access":"public-read"},
+					{"name":"secret","type":"folder","path":"media"}]`
+			case "media/secret":
+				items = `[{"name":"plan","type":"file","path":"media/secret","fileId":"media/secret/plan","format":"pdf","size":6,"access":"private"}]`
+			}
+			writeListFilesPage(w, items)
+		case strings.HasPrefix(r.URL.Path, "/service/platform/assets/v1.0/files/"):
+			fileId := strings.TrimPrefix(r.URL.Path, "/service/platform/assets/v1.0/files/")
+			fmt.Fprintf(w, `{"fileId":%q,"url":"%s/cdn/%s"}`, fileId, srv.URL, fileId)
+		case r.URL.Path == "/cdn/media/logo":
+			atomic.AddInt32(&downloads, 1)
+			fmt.Fprint(w, "\x89PNG")
+		case r.URL.Path == "/cdn/media/secret/plan":
+			atomic.AddInt32(&downloads, 1)
+			if r.URL.Query().Get("pbs") == "" || r.URL.Query().Get("pbt") != "access-key" {
+				w.WriteHeader(http.StatusForbidden)
+				return
+			}
+			fmt.Fprint(w, "%PDF-1")
+		default:
+			t.Errorf("Failed ! unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+	client := newPixelbinFor(srv, nil)
+	dir := t.TempDir()
+
+	report, err := client.Download("media", dir, platform.DownloadOptions{})
+	if err == nil || len(report.Failed()) != 1 || report.Failed()[0].Path != "secret/plan.pdf" {
+		t.Errorf("Failed ! expected the private asset to fail without a token, got %v\n%s", err, report)
+	}
+
+	_, err = client.Download("media", dir, platform.DownloadOptions{AccessKey: "access-key", Token: "token"})
+	if err != nil {
+		t.Fatalf("Failed ! %v", err)
+	}
+	for name, want := range map[string]string{"logo.png": "\x89PNG", "secret/plan.pdf": "%PDF-1"} {
+		got, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(name)))
+		if err != nil || string(got) != want {
+			t.Errorf("Failed ! %s holds %q, %v", name, got, err)
+		}
+	}
+
+	before := atomic.LoadInt32(&downloads)
+	report, err = client.Download("media", dir, platform.DownloadOptions{AccessKey: "access-key", Token: "token"})
+	if err != nil {
+		t.Fatalf("Failed ! %v", err)
+	}
+	if report.Count(platform.SyncSkip) != 2 || atomic.LoadInt32(&downloads) != before {
+		t.Errorf("Failed ! expected files of matching size to be skipped:\n%s", report)
+	}
+}
+
+func TestDeleteWhereBatchesAndReports(t *testing.T) {
+	var mu sync.Mutex
+	var batches [][]string
+	client, srv := newLocalPixelbin(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/service/platform/assets/v1.0/listFiles":
+			q := r.URL.Query()
+			if q.Get("onlyFiles") != "true" || q.Get("format") != "png" || q.Get("tags") != "stale" {
+				t.Errorf("Failed ! unexpected query %v", q)
+			}
+			pageNo, _ := strconv.Atoi(q.Get("pageNo"))
+			var items []string
+			for i := (pageNo - 1) * 10; i < pageNo*10 && i < 25; i++ {
+				items = append(items, fmt.Sprintf(`{"_id":"id-%d","fileId":"tmp/f%d","type":"file"}`, i, i))
+			}
+			fmt.Fprintf(w, `{"items":[%s],"page":{"current":%d,"hasNext":%t}}`, strings.Join(items, ","), pageNo, pageNo*10 < 25)
+		case "/service/platform/assets/v1.0/files/delete":
+			var body struct{ Ids []string }
+			json.NewDecoder(r.Body).Decode(&body)
+			mu.Lock()
+			batches = append(batches, body.Ids)
+			mu.Unlock()
+			var deleted []string
+			for _, id := range body.Ids {
+				if id != "id-7" {
+					deleted = append(deleted, fmt.Sprintf(`{"_id":%q}`, id))
+				}
+			}
+			fmt.Fprintf(w, `[%s]`, strings.Join(deleted, ","))
+		default:
+			t.Errorf("Failed ! unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	})
+	defer srv.Close()
+	query := platform.ListFilesXQuery{Path: "tmp", Format: "png", Tags: []interface{}{"stale"}}
+
+	report, err := client.Assets.DeleteWhere(query, platform.BulkOptions{DryRun: true})
+	if err != nil || len(report.Results) != 25 || len(batches) != 0 || !report.Results[0].Changed {
+		t.Fatalf("Failed ! dry-run selected %d files, sent %d batches, %v", len(report.Results), len(batches), err)
+	}
+
+	report, err = client.Assets.DeleteWhere(query, platform.BulkOptions{BatchSize: 10, Concurrency: 2})
+	if len(batches) != 3 {
+		t.Errorf("Failed ! expected 3 batches of at most 10 IDs, got %v", batches)
+	}
+	for _, batch := range batches {
+		if len(batch) > 10 {
+			t.Errorf("Failed ! batch of %d IDs", len(batch))
+		}
+	}
+	failed := report.Failed()
+	if err == nil || len(failed) != 1 || failed[0].ID != "id-7" || !errors.Is(failed[0].Err, platform.ErrNotDeleted) {
+		t.Errorf("Failed ! expected only id-7 to fail, got %v and %v", failed, err)
+	}
+	if len(report.Succeeded()) != 24 || !report.Succeeded()[0].Changed || failed[0].Changed {
+		t.Errorf("Failed ! expected 24 deletions, got %d", len(report.Succeeded()))
+	}
+}
+
+// fakeAssetStore is an in-memory organization answering the folder and file endpoints used by Assets.Move
+type fakeAssetStore struct {
+	mu      sync.Mutex
+	folders map[string]string // path to _id
+	files   map[string]string // fileId to _id
+	failing map[string]bool   // fileIds whose next update fails
+}
+
+func (s *fakeAssetStore) children(dir string) []string {
+	var items []string
+	for p, id := range s.folders {
+		if path.Dir(p) == dir || (dir == "" && path.Dir(p) == ".") {
+			items = append(items, fmt.Sprintf(`{"_id":%q,"name":%q,"path":%q,"type":"folder"}`, id, path.Base(p), dir))
+		}
+	}
+	for fileId, id := range s.files {
+		if path.Dir(fileId) == dir {
+			items = append(items, fmt.Sprintf(`{"_id":%q,"name":%q,"path":%q,"fileId":%q,"type":"file"}`, id, path.Base(fileId), dir, fileId))
+		}
+	}
+	sort.Strings(items)
+	return items
+}
+
+func (s *fakeAssetStore) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	const prefix = "/service/platform/assets/v1.0/"
+	q := r.URL.Query()
+	switch {
+	case r.URL.Path == prefix+"listFiles":
+		writeListFilesPage(w, "["+strings.Join(s.children(q.Get("path")), ",")+"]")
+	case r.URL.Path == prefix+"folders" && r.Method == http.MethodGet:
+		p := strings.Trim(q.Get("path")+"/"+q.Get("name"), "/")
+		if id, ok := s.folders[p]; ok {
+			fmt.Fprintf(w, `[{"_id":%q,"name":%q,"type":"folder"}]`, id, q.Get("name"))
+			return
+		}
+		fmt.Fprint(w, `[]`)
+	case r.URL.Path == prefix+"folders" && r.Method == http.MethodPost:
+		var body struct{ Name, Path string }
+		json.NewDecoder(r.Body).Decode(&body)
+		p := strings.Trim(body.Path+"/"+body.Name, "/")
+		if _, ok := s.folders[p]; ok {
+			w.WriteHeader(http.StatusConflict)
+			fmt.Fprint(w, `{"message":"folder exists"}`)
+			return
+		}
+		if _, ok := s.folders[body.Path]; body.Path != "" && !ok {
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"message":"parent folder not found"}`)
+			return
+		}
+		s.folders[p] = "folder:" + p
+		fmt.Fprintf(w, `{"_id":%q,"name":%q}`, s.folders[p], body.Name)
+	case strings.HasPrefix(r.URL.Path, prefix+"folders/") && r.Method == http.MethodDelete:
+		id := strings.TrimPrefix(r.URL.Path, prefix+"folders/")
+		for p, folderId := range s.folders {
+			if folderId == id {
+				if len(s.children(p)) > 0 {
+					panic("folder " + p + " deleted while not empty")
+				}
+				delete(s.folders, p)
+			}
+		}
+		fmt.Fprint(w, `{}`)
+	case strings.HasPrefix(r.URL.Path, prefix+"files/") && r.Method == http.MethodGet && s.files[strings.TrimPrefix(r.URL.Path, prefix+"files/")] != "":
+		fileId := strings.TrimPrefix(r.URL.Path, prefix+"files/")
+		fmt.Fprintf(w, `{"_id":%q,"fileId":%q}`, s.files[fileId], fileId)
+	case strings.HasPrefix(r.URL.Path, prefix+"files/") && r.Method == http.MethodPatch:
+		fileId := strings.TrimPrefix(r.URL.Path, prefix+"files/")
+		var body struct{ Name, Path string }
+		json.NewDecoder(r.Body).Decode(&body)
+		id, ok := s.files[fileId]
+		if !ok || s.failing[fileId] {
+			delete(s.failing, fileId)
+			w.WriteHeader(http.StatusInternalServerError)
+			fmt.Fprint(w, `{"message":"update failed"}`)
+			return
+		}
+		name := body.Name
+		if name == "" {
+			name = path.Base(fileId)
+		}
+		delete(s.files, fileId)
+		s.files[strings.Trim(body.Path+"/"+name, "/")] = id
+		fmt.Fprintf(w, `{"_id":%q}`, id)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprint(w, `{"message":"not found"}`)
+	}
+}
+
+func TestMoveFolderIsResumable(t *testing.T) {
+	store := &fakeAssetStore{
+		folders: map[string]string{"a": "folder:a", "a/b": "folder:a/b", "a/b/c": "folder:a/b/c", "keep": "folder:keep"},
+		files:   map[string]string{"a/one": "1", "a/b/two": "2", "a/b/c/three": "3", "keep/four": "4"},
+		failing: map[string]bool{"a/b/two": true},
+	}
+	srv := httptest.NewServer(store)
+	defer srv.Close()
+	assets := newPixelbinFor(srv, nil).Assets
+
+	report, err := assets.Move("a", "x/y")
+	if err == nil || len(report.Failed()) != 1 || report.Failed()[0].FileId != "a/b/two" {
+		t.Fatalf("Failed ! expected a/b/two to fail, got %v", err)
+	}
+	if _, ok := store.folders["a/b"]; !ok {
+		t.Errorf("Failed ! a folder still holding a file was deleted")
+	}
+
+	report, err = assets.Move("a", "x/y")
+	if err != nil || len(report.Results) != 1 {
+		t.Fatalf("Failed ! resuming the move: %v, %+v", err, report)
+	}
+	var files, folders []string
+	for fileId := range store.files {
+		files = append(files, fileId)
+	}
+	for p := range store.folders {
+		folders = append(folders, p)
+	}
+	sort.Strings(files)
+	sort.Strings(folders)
+	if got := strings.Join(files, " "); got != "keep/four x/y/b/c/three x/y/b/two x/y/one" {
+		t.Errorf("Failed ! files after move: %s", got)
+	}
+	if got := strings.Join(folders, " "); got != "keep x x/y x/y/b x/y/b/c" {
+		t.Errorf("Failed ! folders after move: %s", got)
+	}
+
+	// the source is gone and the destination is there, the move is already done
+	report, err = assets.Move("a", "x/y")
+	if err != nil || len(report.Results) != 0 {
+		t.Fatalf("Failed ! moving again after the move completed: %v, %+v", err, report)
+	}
+
+	// the missing folders of the destination are created root first
+	if _, err = assets.Move("keep/four", "archive/2023/renamed"); err != nil {
+		t.Fatalf("Failed ! moving a file: %v", err)
+	}
+	if _, ok := store.files["archive/2023/renamed"]; !ok {
+		t.Errorf("Failed ! file was not renamed: %v", store.files)
+	}
+	if _, ok := store.folders["archive"]; !ok {
+		t.Errorf("Failed ! expected the archive folder to be created: %v", store.folders)
+	}
+	if report, err = assets.Move("keep/four", "archive/2023/renamed"); err != nil || len(report.Results) != 0 {
+		t.Errorf("Failed ! moving a file again after the move completed: %v, %+v", err, report)
+	}
+	if _, err = assets.Move("keep/missing", "x/missing"); err == nil {
+		t.Errorf("Failed ! expected moving a missing file to fail")
+	}
+}
+
+func TestBulkTagAndMetadataEdits(t *testing.T) {
+	var mu sync.Mutex
+	files := map[string]map[string]interface{}{
+		"p/one":   {"_id": "1", "tags": []interface{}{"sale"}, "metadata": map[string]interface{}{"sku": "A1", "dims": map[string]interface{}{"w": 1.0, "h": 2.0}}},
+		"p/two":   {"_id": "2", "tags": []interface{}{"new"}, "metadata": map[string]interface{}{}},
+		"p/three": {"_id": "3", "tags": []interface{}{"sale", "new"}},
+	}
+	patches, reads := 0, 0
+	client, srv := newLocalPixelbin(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		const prefix = "/service/platform/assets/v1.0/files/"
+		if r.URL.Path == "/service/platform/assets/v1.0/listFiles" {
+			writeListFilesPage(w, `[{"_id":"1","fileId":"p/one","type":"file"},{"_id":"2","fileId":"p/two","type":"file"},{"name":"sub","type":"folder"}]`)
+			return
+		}
+		file, ok := files[strings.TrimPrefix(r.URL.Path, prefix)]
+		if !strings.HasPrefix(r.URL.Path, prefix) || !ok {
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"message":"file not found"}`)
+			return
+		}
+		if r.Method == http.MethodPatch {
+			patches++
+			var body map[string]interface{}
+			json.NewDecoder(r.Body).Decode(&body)
+			for k, v := range body {
+				file[k] = v
+			}
+		} else {
+			reads++
+		}
+		json.NewEncoder(w).Encode(file)
+	})
+	defer srv.Close()
+
+	// the handler updates files, patches and reads concurrently, so they are only read under mu
+	tags := func() string {
+		mu.Lock()
+		defer mu.Unlock()
+		return fmt.Sprint(files["p/one"]["tags"], files["p/two"]["tags"], files["p/three"]["tags"])
+	}
+	counts := func() (int, int) {
+		mu.Lock()
+		defer mu.Unlock()
+		return patches, reads
+	}
+
+	target := platform.BulkTarget{FileIds: []string{"p/one", "p/two", "p/three", "p/missing"}}
+	report, err := client.Assets.AddTags(target, []string{"sale"}, platform.BulkOptions{DryRun: true})
+	if n, _ := counts(); len(report.Results) != 4 || report.Results[0].Changed || !report.Results[1].Changed || report.Results[2].Changed || report.Results[3].Changed || n != 0 {
+		t.Errorf("Failed ! expected the dry run to mark only p/two as changed, got %+v after %d updates", report.Results, n)
+	}
+
+	report, err = client.Assets.AddTags(target, []string{"sale"}, platform.BulkOptions{})
+	if len(report.Failed()) != 1 || !common.IsNotFound(report.Failed()[0].Err) || err == nil {
+		t.Errorf("Failed ! expected only p/missing to fail, got %v", err)
+	}
+	if n, _ := counts(); tags() != "[sale] [new sale] [sale new]" || n != 1 || !report.Results[1].Changed || report.Results[0].Changed {
+		t.Errorf("Failed ! expected only p/two to be updated, got tags %s after %d updates", tags(), n)
+	}
+
+	query := &platform.ListFilesXQuery{Path: "p"}
+	if _, err = client.Assets.RemoveTags(platform.BulkTarget{Query: query}, []string{"sale", "new"}, platform.BulkOptions{}); err != nil {
+		t.Fatalf("Failed ! %v", err)
+	}
+	if got := tags(); got != "[] [] [sale new]" {
+		t.Errorf("Failed ! tags after removal: %s", got)
+	}
+
+	_, err = client.Assets.PatchMetadata(platform.BulkTarget{FileIds: []string{"p/one", "p/two", "p/three"}}, map[string]interface{}{
+		"sku":  nil,
+		"dims": map[string]interface{}{"h": nil, "d": 3},
+		"seen": true,
+	}, platform.BulkOptions{})
+	if err != nil {
+		t.Fatalf("Failed ! %v", err)
+	}
+	mu.Lock()
+	one, three := fmt.Sprint(files["p/one"]["metadata"]), fmt.Sprint(files["p/three"]["metadata"])
+	mu.Unlock()
+	if one != "map[dims:map[d:3 w:1] seen:true]" {
+		t.Errorf("Failed ! merged metadata %s", one)
+	}
+	if three != "map[dims:map[d:3] seen:true]" {
+		t.Errorf("Failed ! merged metadata %s", three)
+	}
+
+	// the int of the patch matches the float64 decoded from the stored metadata
+	before, _ := counts()
+	report, err = client.Assets.PatchMetadata(platform.BulkTarget{FileIds: []string{"p/one"}}, map[string]interface{}{"dims": map[string]interface{}{"d": 3}}, platform.BulkOptions{})
+	if n, _ := counts(); err != nil || report.Results[0].Changed || n != before {
+		t.Errorf("Failed ! expected an unchanged file not to be updated, got %d updates, %v", n-before, err)
+	}
+
+	// at 10 files per second, files are started 100ms apart: stopping after 250ms leaves time for 3 of them at most
+	_, before = counts()
+	ctx, cancel := context.WithTimeout(context.Background(), 250*time.Millisecond)
+	defer cancel()
+	many := platform.BulkTarget{FileIds: make([]string, 20)}
+	for i := range many.FileIds {
+		many.FileIds[i] = "p/three"
+	}
+	report, err = client.Assets.AddTagsWithContext(ctx, many, []string{"new"}, platform.BulkOptions{RateLimit: 10, Concurrency: 20})
+	if _, n := counts(); n-before > 3 || n-before < 1 {
+		t.Errorf("Failed ! expected 1 to 3 files read in 250ms at 10 per second, got %d", n-before)
+	}
+	if !errors.Is(err, context.DeadlineExceeded) || len(report.Failed()) < 17 {
+		t.Errorf("Failed ! expected the files not started in time to fail, got %d failures, %v", len(report.Failed()), err)
+	}
+}
+
+// fakeResumableServer is a multipart upload server recording every part. It rejects the parts listed in failing
+// and answers 503 to the parts listed in unavailable, as many times as given. Parts are received concurrently,
+// each held for delay, and the peak number of parts in flight is kept in maxInFlight.
+// Any other request, such as an attempt to abort the session, fails the test.
+type fakeResumableServer struct {
+	*httptest.Server
+	mu          sync.Mutex
+	failing     map[string]bool
+	unavailable map[string]int
+	delay       time.Duration
+	signed      int
+	parts       map[string]string
+	completed   []int
+	inFlight    int
+	maxInFlight int
+	// received is the number of bytes of the stored parts, updated atomically before each part is answered
+	received int64
+}
+
+func newFakeResumableServer(t *testing.T) *fakeResumableServer {
+	f := &fakeResumableServer{failing: map[string]bool{}, unavailable: map[string]int{}, parts: map[string]string{}}
+	f.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/service/platform/assets/v2.0/upload/signed-url":
+			f.mu.Lock()
+			f.signed++
+			f.mu.Unlock()
+			fmt.Fprintf(w, `{"presignedUrl":{"url":"%s/upload","fields":{"x-pixb-meta-assetdata":"{}"}}}`, f.URL)
+		case r.URL.Path == "/upload" && r.Method == http.MethodPut:
+			f.uploadPart(t, w, r)
+		case r.URL.Path == "/upload" && r.Method == http.MethodPost:
+			var body struct{ Parts []int }
+			json.NewDecoder(r.Body).Decode(&body)
+			f.mu.Lock()
+			f.completed = body.Parts
+			f.mu.Unlock()
+			fmt.Fprint(w, `{"name":"myimage"}`)
+		default:
+			t.Errorf("Failed ! unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	return f
+}
+
+func (f *fakeResumableServer) uploadPart(t *testing.T, w http.ResponseWriter, r *http.Request) {
+	partNumber := r.URL.Query().Get("partNumber")
+	f.mu.Lock()
+	if f.failing[partNumber] {
+		f.mu.Unlock()
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprint(w, `{"message":"part rejected"}`)
+		return
+	}
+	if f.unavailable[partNumber] > 0 {
+		f.unavailable[partNumber]--
+		f.mu.Unlock()
+		w.Header().Set("Retry-After", "0")
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+	f.inFlight++
+	if f.inFlight > f.maxInFlight {
+		f.maxInFlight = f.inFlight
+	}
+	f.mu.Unlock()
+
+	var data []byte
+	file, _, err := r.FormFile("file")
+	if err != nil {
+		t.Errorf("Failed ! part %s has no file: %v", partNumber, err)
+	} else {
+		data, _ = io.ReadAll(file)
+		time.Sleep(f.delay)
+	}
+	f.mu.Lock()
+	f.inFlight--
+	if err == nil {
+		f.parts[partNumber] = string(data)
+	}
+	f.mu.Unlock()
+	atomic.AddInt64(&f.received, int64(len(data)))
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func TestResumeUploadSkipsCheckpointedParts(t *testing.T) {
+	srv := newFakeResumableServer(t)
+	defer srv.Close()
+	client := newPixelbinFor(srv.Server, nil)
+	store := platform.NewFileCheckpointStore(t.TempDir())
+
+	data := "0123456789abcdefghi"
+	srv.failing["3"] = true
+	_, err := client.Uploader.Upload(strings.NewReader(data), platform.UploaderUploadXQuery{Name: "myimage"},
+		platform.WithChunkSize(4),
+		platform.WithConcurrency(1),
+		platform.WithCheckpoint("myimage"),
+		platform.WithCheckpointStore(store),
+	)
+	if err == nil {
+		t.Fatalf("Failed ! expected the upload to fail on part 3")
+	}
+	checkpoint, err := store.Load("myimage")
+	if err != nil || checkpoint == nil {
+		t.Fatalf("Failed ! expected a checkpoint, got %v, %v", checkpoint, err)
+	}
+	if checkpoint.ChunkSize != 4 || !strings.HasSuffix(checkpoint.UploadURL, "/upload") {
+		t.Errorf("Failed ! unexpected checkpoint %+v", checkpoint)
+	}
+	uploaded := map[int]bool{}
+	for _, part := range checkpoint.CompletedParts {
+		uploaded[part] = true
+	}
+	if !uploaded[1] || !uploaded[2] || uploaded[3] {
+		t.Fatalf("Failed ! expected parts 1 and 2 but not 3 in the checkpoint, got %v", checkpoint.CompletedParts)
+	}
+
+	srv.mu.Lock()
+	srv.failing = map[string]bool{}
+	srv.parts = map[string]string{}
+	srv.mu.Unlock()
+	result, err := client.Uploader.ResumeUpload(strings.NewReader(data), "myimage",
+		platform.WithChunkSize(64),
+		platform.WithCheckpointStore(store),
+	)
+	if err != nil {
+		t.Fatalf("Failed ! got err %v", err)
+	}
+	if result["name"] != "myimage" {
+		t.Errorf("Failed ! unexpected result %v", result)
+	}
+
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+	if srv.signed != 1 {
+		t.Errorf("Failed ! resuming must reuse the signed URL, got %d signed URL calls", srv.signed)
+	}
+	expected := map[string]string{"1": "0123", "2": "4567", "3": "89ab", "4": "cdef", "5": "ghi"}
+	for part := range srv.parts {
+		if n, _ := strconv.Atoi(part); uploaded[n] {
+			t.Errorf("Failed ! part %s was uploaded again", part)
+		}
+	}
+	for part, content := range expected {
+		n, _ := strconv.Atoi(part)
+		if !uploaded[n] && srv.parts[part] != content {
+			t.Errorf("Failed ! expected part %s to be %q, got %q", part, content, srv.parts[part])
+		}
+	}
+	if fmt.Sprint(srv.completed) != "[1 2 3 4 5]" {
+		t.Errorf("Failed ! expected 5 parts completed, got %v", srv.completed)
+	}
+	if checkpoint, _ = store.Load("myimage"); checkpoint != nil {
+		t.Errorf("Failed ! the checkpoint must be deleted once the upload completes")
+	}
+
+	if _, err = client.Uploader.ResumeUpload(strings.NewReader(data), "missing", platform.WithCheckpointStore(store)); err == nil {
+		t.Errorf("Failed ! resuming without checkpoint must fail")
+	}
+}
+
+func TestUploadReportsProgress(t *testing.T) {
+	srv := newFakeResumableServer(t)
+	defer srv.Close()
+	client := newPixelbinFor(srv.Server, nil)
+	client.Config.SetRetryPolicy(&platform.RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond})
+	srv.unavailable["2"] = 2
+
+	var updates []platform.UploadProgress
+	_, err := client.Uploader.Upload(strings.NewReader("0123456789"), platform.UploaderUploadXQuery{Name: "myimage"},
+		platform.WithChunkSize(4),
+		platform.WithProgress(func(p platform.UploadProgress) {
+			// calls are serialised, so no lock is needed
+			updates = append(updates, p)
+		}),
+	)
+	if err != nil {
+		t.Fatalf("Failed ! got err %v", err)
+	}
+	last := updates[len(updates)-1]
+	if last.BytesSent != 10 || last.TotalBytes != 10 || last.PartsCompleted != 3 || last.TotalParts != 3 || last.Retries != 2 {
+		t.Errorf("Failed ! unexpected final progress %+v", last)
+	}
+	retriesOfPart2, completions, retries := 0, 0, 0
+	var previous platform.UploadProgress
+	for _, p := range updates {
+		if p.BytesSent < previous.BytesSent || p.Elapsed < previous.Elapsed || p.BytesSent > 10 {
+			t.Errorf("Failed ! progress went backwards or too far: %+v after %+v", p, previous)
+		}
+		if p.PartsCompleted > previous.PartsCompleted {
+			completions++
+		}
+		if p.Retries > previous.Retries {
+			retries++
+		}
+		previous = p
+		if p.Part == 2 && p.PartRetries > retriesOfPart2 {
+			retriesOfPart2 = p.PartRetries
+		}
+		if p.Part != 2 && p.PartRetries != 0 {
+			t.Errorf("Failed ! part %d was not retried, got %+v", p.Part, p)
+		}
+	}
+	if retriesOfPart2 != 2 || completions != 3 || retries != 2 {
+		t.Errorf("Failed ! expected 3 completions and 2 retries of part 2, got %d, %d and %d", completions, retries, retriesOfPart2)
+	}
+
+	updates = nil
+	_, err = client.Uploader.Upload(io.MultiReader(strings.NewReader("0123456789")), platform.UploaderUploadXQuery{Name: "myimage"},
+		platform.WithChunkSize(4),
+		platform.WithProgress(func(p platform.UploadProgress) { updates = append(updates, p) }),
+	)
+	if err != nil {
+		t.Fatalf("Failed ! got err %v", err)
+	}
+	if last = updates[len(updates)-1]; last.TotalBytes != -1 || last.TotalParts != 0 || last.BytesSent != 10 {
+		t.Errorf("Failed ! expected an unknown size for a plain reader, got %+v", last)
+	}
+}
+
+func TestUploadReportsBytesWhilePartsAreSent(t *testing.T) {
+	srv := newFakeResumableServer(t)
+	defer srv.Close()
+	client := newPixelbinFor(srv.Server, nil)
+
+	const size = 1 << 20
+	var during []int64
+	_, err := client.Uploader.Upload(bytes.NewReader(make([]byte, size)), platform.UploaderUploadXQuery{Name: "myimage"},
+		platform.WithChunkSize(size),
+		platform.WithProgress(func(p platform.UploadProgress) {
+			if p.PartsCompleted == 0 {
+				during = append(during, p.BytesSent)
+			}
+		}),
+	)
+	if err != nil {
+		t.Fatalf("Failed ! got err %v", err)
+	}
+	// the only part is reported while its body is written, not just once it completes
+	if len(during) < 2 || during[0] <= 0 || during[0] >= size {
+		t.Errorf("Failed ! expected several updates while the part was sent, got %v", during)
+	}
+}
+
+// aheadReader checks on every read that the uploader does not read further than limit bytes past the completed parts
+type aheadReader struct {
+	t         *testing.T
+	r         io.Reader
+	read      int64
+	completed *int64
+	limit     int64
+}
+
+func (a *aheadReader) Read(p []byte) (int, error) {
+	n, err := a.r.Read(p)
+	a.read += int64(n)
+	if ahead := a.read - atomic.LoadInt64(a.completed); ahead > a.limit {
+		a.t.Errorf("Failed ! read %d bytes ahead of the completed parts, expected at most %d", ahead, a.limit)
+	}
+	return n, err
+}
+
+func TestUploadReadsAtMostConcurrencyChunksAhead(t *testing.T) {
+	srv := newFakeResumableServer(t)
+	defer srv.Close()
+	srv.delay = 5 * time.Millisecond
+	client := newPixelbinFor(srv.Server, nil)
+
+	data := strings.Repeat("0123456789", 20)
+	reader := &aheadReader{t: t, r: strings.NewReader(data), completed: &srv.received, limit: 2 * 8}
+	_, err := client.Uploader.Upload(reader, platform.UploaderUploadXQuery{Name: "myimage"},
+		platform.WithChunkSize(8),
+		platform.WithConcurrency(2),
+	)
+	if err != nil {
+		t.Fatalf("Failed ! got err %v", err)
+	}
+	if srv.received != int64(len(data)) || len(srv.completed) != 25 {
+		t.Errorf("Failed ! expected %d bytes in 25 parts, got %d bytes in %d parts", len(data), srv.received, len(srv.completed))
+	}
+	if srv.maxInFlight > 2 {
+		t.Errorf("Failed ! expected at most 2 parts in flight, got %d", srv.maxInFlight)
+	}
+}
+
+// lateReadingTransport answers upload parts at once and reads their body afterwards, as the RoundTripper
+// contract allows, checking that the body still holds its part
+type lateReadingTransport struct {
+	t  *testing.T
+	wg sync.WaitGroup
+}
+
+func (l *lateReadingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method != http.MethodPut {
+		return http.DefaultTransport.RoundTrip(req)
+	}
+	part, _ := strconv.Atoi(req.URL.Query().Get("partNumber"))
+	l.wg.Add(1)
+	go func() {
+		defer l.wg.Done()
+		defer req.Body.Close()
+		time.Sleep(5 * time.Millisecond)
+		data, _ := io.ReadAll(req.Body)
+		if want := strings.Repeat(string(rune('a'+part-1)), 4); !bytes.Contains(data, []byte(want)) {
+			l.t.Errorf("Failed ! part %d was overwritten before the transport read it: %q", part, data)
+		}
+	}()
+	return &http.Response{StatusCode: http.StatusNoContent, Header: http.Header{}, Body: http.NoBody, Request: req}, nil
+}
+
+func TestUploadKeepsPartBuffersUntilTheTransportClosesTheBody(t *testing.T) {
+	srv := newFakeResumableServer(t)
+	defer srv.Close()
+	transport := &lateReadingTransport{t: t}
+	client := newPixelbinFor(srv.Server, transport)
+
+	var data strings.Builder
+	for part := 0; part < 8; part++ {
+		data.WriteString(strings.Repeat(string(rune('a'+part)), 4))
+	}
+	_, err := client.Uploader.Upload(strings.NewReader(data.String()), platform.UploaderUploadXQuery{Name: "myimage"},
+		platform.WithChunkSize(4),
+		platform.WithConcurrency(1),
+	)
+	transport.wg.Wait()
+	if err != nil {
+		t.Fatalf("Failed ! %v", err)
+	}
+	if fmt.Sprint(srv.completed) != "[1 2 3 4 5 6 7 8]" {
+		t.Errorf("Failed ! expected 8 parts to be completed, got %v", srv.completed)
+	}
+}
+
+// rejectingTransport answers 403 to the given upload parts once all of them are in flight, so that
+// none is cancelled by the failure of another, and passes every other request through
+type rejectingTransport struct {
+	parts   map[string]bool
+	arrived chan struct{}
+	all     chan struct{}
+}
+
+func (r *rejectingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	part := req.URL.Query().Get("partNumber")
+	if req.Method != http.MethodPut || !r.parts[part] {
+		return http.DefaultTransport.RoundTrip(req)
+	}
+	req.Body.Close()
+	r.arrived <- struct{}{}
+	if len(r.arrived) == len(r.parts) {
+		close(r.all)
+	}
+	<-r.all
+	return &http.Response{
+		StatusCode: http.StatusForbidden,
+		Header:     http.Header{},
+		Body:       io.NopCloser(strings.NewReader(fmt.Sprintf(`{"message":"part %s rejected"}`, part))),
+		Request:    req,
+	}, nil
+}
+
+func TestUploadFailsFastAndListsEveryFailedPart(t *testing.T) {
+	srv := newFakeResumableServer(t)
+	defer srv.Close()
+	client := newPixelbinFor(srv.Server, &rejectingTransport{
+		parts:   map[string]bool{"2": true, "4": true},
+		arrived: make(chan struct{}, 2),
+		all:     make(chan struct{}),
+	})
+
+	reader := &aheadReader{t: t, r: strings.NewReader(strings.Repeat("0123456789", 40)), completed: new(int64), limit: 1 << 30}
+	_, err := client.Uploader.Upload(reader, platform.UploaderUploadXQuery{Name: "myimage"},
+		platform.WithChunkSize(4),
+		platform.WithConcurrency(4),
+	)
+	var uploadErr *platform.UploadError
+	if !errors.As(err, &uploadErr) {
+		t.Fatalf("Failed ! expected an UploadError, got %v", err)
+	}
+	parts := uploadErr.Parts()
+	if len(parts) != 2 || parts[0].PartNumber != 2 || parts[1].PartNumber != 4 {
+		t.Fatalf("Failed ! expected parts 2 and 4 to fail, got %+v", parts)
+	}
+	if !errors.Is(parts[1].Err, common.ErrForbidden) {
+		t.Errorf("Failed ! expected the cause of part 4, got %v", parts[1].Err)
+	}
+	if !strings.Contains(err.Error(), "part 2: ") || !strings.Contains(err.Error(), "part 4: ") {
+		t.Errorf("Failed ! expected every failed part in %q", err)
+	}
+	if !errors.Is(err, common.ErrForbidden) {
+		t.Errorf("Failed ! expected the part errors to be unwrapped, got %v", err)
+	}
+
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+	if reader.read >= 400 || len(srv.parts) >= 98 {
+		t.Errorf("Failed ! expected the upload to stop early, read %d bytes and uploaded %d parts", reader.read, len(srv.parts))
+	}
+	if srv.completed != nil {
+		t.Errorf("Failed ! expected the session not to be completed, got %v", srv.completed)
+	}
+}
+
+func TestUploadKeepsDefaultAttemptsUnderPartialRetryPolicy(t *testing.T) {
+	srv := newFakeResumableServer(t)
+	defer srv.Close()
+	client := newPixelbinFor(srv.Server, nil)
+	// only the delay is set, the 3 upload attempts by default still apply
+	client.Config.SetRetryPolicy(&platform.RetryPolicy{BaseDelay: time.Millisecond})
+	srv.unavailable["1"] = 2
+
+	if _, err := client.Uploader.Upload(strings.NewReader("0123"), platform.UploaderUploadXQuery{Name: "myimage"}); err != nil {
+		t.Fatalf("Failed ! expected the part to succeed on its third attempt, got %v", err)
+	}
+}
+
+// countingReaderAt counts the reads starting at every offset
+type countingReaderAt struct {
+	r     io.ReaderAt
+	mu    sync.Mutex
+	reads map[int64]int
+}
+
+func (c *countingReaderAt) ReadAt(p []byte, off int64) (int, error) {
+	c.mu.Lock()
+	c.reads[off]++
+	c.mu.Unlock()
+	return c.r.ReadAt(p, off)
+}
+
+func TestUploadReaderAtAndFileSplitExactParts(t *testing.T) {
+	srv := newFakeResumableServer(t)
+	defer srv.Close()
+	client := newPixelbinFor(srv.Server, nil)
+	client.Config.SetRetryPolicy(&platform.RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond})
+	srv.unavailable["2"] = 1
+
+	data := "0123456789abcdefghi"
+	reader := &countingReaderAt{r: strings.NewReader(data), reads: map[int64]int{}}
+	var last platform.UploadProgress
+	result, err := client.Uploader.UploadReaderAt(reader, int64(len(data)), platform.UploaderUploadXQuery{Name: "myimage"},
+		platform.WithChunkSize(4),
+		platform.WithProgress(func(p platform.UploadProgress) { last = p }),
+	)
+	if err != nil {
+		t.Fatalf("Failed ! got err %v", err)
+	}
+	if result["name"] != "myimage" {
+		t.Errorf("Failed ! unexpected result %v", result)
+	}
+	expected := map[string]string{"1": "0123", "2": "4567", "3": "89ab", "4": "cdef", "5": "ghi"}
+	srv.mu.Lock()
+	if fmt.Sprint(srv.parts) != fmt.Sprint(expected) || fmt.Sprint(srv.completed) != "[1 2 3 4 5]" {
+		t.Errorf("Failed ! expected parts %v completed, got %v completed as %v", expected, srv.parts, srv.completed)
+	}
+	srv.mu.Unlock()
+	reader.mu.Lock()
+	if reader.reads[4] < 2 {
+		t.Errorf("Failed ! expected the retried part 2 to be read again, got reads %v", reader.reads)
+	}
+	reader.mu.Unlock()
+	if last.TotalParts != 5 || last.PartsCompleted != 5 || last.BytesSent != int64(len(data)) || last.Retries != 1 {
+		t.Errorf("Failed ! unexpected final progress %+v", last)
+	}
+
+	path := filepath.Join(t.TempDir(), "myimage.jpeg")
+	if err = os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	srv.mu.Lock()
+	srv.parts = map[string]string{}
+	srv.mu.Unlock()
+	if _, err = client.Uploader.UploadFile(path, platform.UploaderUploadXQuery{Name: "myimage"}, platform.WithChunkSize(8)); err != nil {
+		t.Fatalf("Failed ! got err %v", err)
+	}
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+	if expected = map[string]string{"1": "01234567", "2": "89abcdef", "3": "ghi"}; fmt.Sprint(srv.parts) != fmt.Sprint(expected) {
+		t.Errorf("Failed ! expected parts %v, got %v", expected, srv.parts)
+	}
+	if _, err = client.Uploader.UploadFile(t.TempDir(), platform.UploaderUploadXQuery{Name: "myimage"}); err == nil {
+		t.Errorf("Failed ! uploading a directory must fail")
+	}
+}
@@ -1,3 +1,3 @@
 package main
 
-var version = "3.1.0"
+var version = "3.2.0"